@@ -0,0 +1,406 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blacktop/go-vboxapi/vboxapi"
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// metricsPeriod is the collection period, in seconds, of the performance
+// metrics set up by the exporter.
+const metricsPeriod = 5
+
+// performanceMetrics are the performance collector metrics exported for the
+// host and for running VMs.
+var performanceMetrics = []string{
+	"CPU/Load/User",
+	"CPU/Load/Kernel",
+	"RAM/Usage/Used",
+	"Net/Rate/Rx",
+	"Net/Rate/Tx",
+	"Disk/Usage/Used",
+	"Guest/CPU/Load/User",
+	"Guest/CPU/Load/Kernel",
+	"Guest/RAM/Usage/Used",
+}
+
+// unitSuffixes maps performance collector units to metric name suffixes.
+var unitSuffixes = map[string]string{
+	"%":   "percent",
+	"B":   "bytes",
+	"kB":  "kilobytes",
+	"MB":  "megabytes",
+	"B/s": "bytes_per_second",
+	"MHz": "megahertz",
+}
+
+var additionsRunLevels = map[vboxweb.AdditionsRunLevelType]float64{
+	vboxweb.AdditionsRunLevelTypeNone:     0,
+	vboxweb.AdditionsRunLevelTypeSystem:   1,
+	vboxweb.AdditionsRunLevelTypeUserland: 2,
+	vboxweb.AdditionsRunLevelTypeDesktop:  3,
+}
+
+// soapStats counts the SOAP calls made to vboxwebsrv per method.
+type soapStats struct {
+	mu    sync.Mutex
+	calls map[string]*soapCallStats
+}
+
+type soapCallStats struct {
+	count   uint64
+	faults  uint64
+	errors  uint64
+	seconds float64
+}
+
+func newSOAPStats() *soapStats {
+	return &soapStats{calls: make(map[string]*soapCallStats)}
+}
+
+// observe is a vboxweb.CallObserver.
+func (s *soapStats) observe(method string, duration time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.calls[method]
+	if !ok {
+		c = &soapCallStats{}
+		s.calls[method] = c
+	}
+	c.count++
+	c.seconds += duration.Seconds()
+	if _, ok := err.(*vboxweb.SOAPFault); ok {
+		c.faults++
+	} else if err != nil {
+		c.errors++
+	}
+}
+
+func (s *soapStats) collect(ms *metricSet) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for method, c := range s.calls {
+		l := labels{"method", method}
+		ms.add("vbox_soap_request_duration_seconds", "summary", "Time spent in SOAP calls to vboxwebsrv.", "_count", l, float64(c.count))
+		ms.add("vbox_soap_request_duration_seconds", "summary", "Time spent in SOAP calls to vboxwebsrv.", "_sum", l, c.seconds)
+		ms.add("vbox_soap_faults_total", "counter", "SOAP calls answered with a fault.", "", l, float64(c.faults))
+		ms.add("vbox_soap_errors_total", "counter", "SOAP calls that failed without a fault, e.g. on connection errors.", "", l, float64(c.errors))
+	}
+}
+
+// exporter serves VirtualBox metrics in the Prometheus text format.
+type exporter struct {
+	virtualbox *vboxapi.VirtualBox
+	collector  *vboxapi.PerformanceCollector
+	host       *vboxapi.Host
+	soap       *soapStats
+
+	// mu serialises scrapes, as the web session has a single session
+	// object to lock machines with.
+	mu sync.Mutex
+	// setup maps the IDs of the running machines metrics are set up for to
+	// the time they last changed state, so a restarted machine is set up
+	// again.
+	setup map[string]time.Time
+}
+
+func newExporter(vb *vboxapi.VirtualBox, soap *soapStats) (*exporter, error) {
+	pc, err := vb.GetPerformanceCollector()
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := vb.GetHost()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := pc.SetupMetrics(performanceMetrics, metricsPeriod, 1, host); err != nil {
+		return nil, err
+	}
+
+	return &exporter{
+		virtualbox: vb,
+		collector:  pc,
+		host:       host,
+		soap:       soap,
+		setup:      make(map[string]time.Time),
+	}, nil
+}
+
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ms := newMetricSet()
+	if err := e.collect(ms); err != nil {
+		log.Printf("Unable to collect metrics: %v\n", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	e.soap.collect(ms)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	ms.writeTo(w)
+}
+
+func (e *exporter) collect(ms *metricSet) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	samples, err := e.collector.QueryMetricsData(performanceMetrics, e.host)
+	if err != nil {
+		return err
+	}
+	addPerformanceSamples(ms, "vbox_host_", nil, samples)
+
+	if err := e.collectMachines(ms); err != nil {
+		return err
+	}
+	return e.collectMedia(ms)
+}
+
+func (e *exporter) collectMachines(ms *metricSet) error {
+	machines, err := e.virtualbox.GetMachines()
+	if err != nil {
+		return err
+	}
+
+	running := make(map[string]bool)
+	for _, m := range machines {
+		if id := e.collectMachine(ms, m); id != "" {
+			running[id] = true
+		}
+		m.Release()
+	}
+
+	for id := range e.setup {
+		if !running[id] {
+			delete(e.setup, id)
+		}
+	}
+	return nil
+}
+
+// collectMachine adds the metrics of m to ms, logging and skipping those
+// that cannot be read. It returns the ID of m if the machine is running.
+func (e *exporter) collectMachine(ms *metricSet, m *vboxapi.Machine) string {
+	name, err := m.GetName()
+	if err != nil {
+		log.Printf("Unable to read machine name: %v\n", err)
+		return ""
+	}
+	id, err := m.GetID()
+	if err != nil {
+		log.Printf("Unable to read ID of %s: %v\n", name, err)
+		return ""
+	}
+	state, err := m.GetState()
+	if err != nil {
+		log.Printf("Unable to read state of %s: %v\n", name, err)
+		return ""
+	}
+
+	l := labels{"machine", name, "id", id}
+	ms.add("vbox_machine_state", "gauge", "State of the VM, set to 1 for the current state.", "", append(l, "state", string(*state)), 1)
+	if *state != vboxweb.MachineStateRunning {
+		return ""
+	}
+
+	if err := e.collectPerformance(ms, m, id, l); err != nil {
+		log.Printf("Unable to read performance metrics of %s: %v\n", name, err)
+	}
+	if err := e.collectAdditions(ms, m, l); err != nil {
+		log.Printf("Unable to read guest additions of %s: %v\n", name, err)
+	}
+	return id
+}
+
+// collectPerformance adds the performance metrics of the running machine m
+// to ms, setting them up first if the machine started since the last
+// scrape.
+func (e *exporter) collectPerformance(ms *metricSet, m *vboxapi.Machine, id string, l labels) error {
+	changed, err := m.GetLastStateChange()
+	if err != nil {
+		return err
+	}
+	if setup, ok := e.setup[id]; !ok || !setup.Equal(changed) {
+		if _, err := e.collector.SetupMetrics(performanceMetrics, metricsPeriod, 1, m); err != nil {
+			return err
+		}
+		e.setup[id] = changed
+	}
+
+	samples, err := e.collector.QueryMetricsData(performanceMetrics, m)
+	if err != nil {
+		return err
+	}
+	addPerformanceSamples(ms, "vbox_machine_", l, samples)
+	return nil
+}
+
+func (e *exporter) collectAdditions(ms *metricSet, m *vboxapi.Machine, l labels) error {
+	session, err := e.virtualbox.GetSession()
+	if err != nil {
+		return err
+	}
+	if err := m.Lock(session, vboxweb.LockTypeShared); err != nil {
+		return err
+	}
+	defer m.Unlock(session)
+
+	console, err := session.GetConsole()
+	if err != nil {
+		return err
+	}
+	defer console.Release()
+
+	guest, err := console.Guest()
+	if err != nil {
+		return err
+	}
+	defer guest.Release()
+
+	runLevel, err := guest.GetAdditionsRunLevel()
+	if err != nil {
+		return err
+	}
+	version, err := guest.GetAdditionsVersion()
+	if err != nil {
+		return err
+	}
+
+	ms.add("vbox_machine_guest_additions_run_level", "gauge", "Guest Additions run level: 0 none, 1 system, 2 userland, 3 desktop.", "", l, additionsRunLevels[*runLevel])
+	ms.add("vbox_machine_guest_additions_info", "gauge", "Guest Additions version, set to 1.", "", append(l, "version", version), 1)
+	return nil
+}
+
+func (e *exporter) collectMedia(ms *metricSet) error {
+	hardDisks, err := e.virtualbox.GetHardDisk("")
+	if err != nil {
+		return err
+	}
+
+	for _, m := range hardDisks.Media() {
+		if _, err := m.Get(); err != nil {
+			log.Printf("Unable to read medium: %v\n", err)
+			m.Release()
+			continue
+		}
+
+		l := labels{"medium", m.Name, "id", m.ID, "location", m.Location}
+		ms.add("vbox_medium_size_bytes", "gauge", "Size of the medium on disk.", "", l, float64(m.Size))
+		ms.add("vbox_medium_logical_size_bytes", "gauge", "Logical size of the medium as seen by the guest.", "", l, float64(m.LogicalSize))
+		m.Release()
+	}
+	return nil
+}
+
+// addPerformanceSamples adds the latest value of each sample as a gauge
+// named after the metric and its unit, e.g. vbox_host_cpu_load_user_percent.
+func addPerformanceSamples(ms *metricSet, prefix string, l labels, samples []*vboxapi.MetricSample) {
+	for _, s := range samples {
+		if len(s.Values) == 0 {
+			continue
+		}
+
+		name := prefix + metricName(s.Name)
+		if suffix, ok := unitSuffixes[s.Unit]; ok {
+			name += "_" + suffix
+		} else if s.Unit != "" {
+			name += "_" + metricName(s.Unit)
+		}
+		ms.add(name, "gauge", fmt.Sprintf("VirtualBox performance metric %s in %s.", s.Name, s.Unit), "", l, s.Last())
+	}
+}
+
+// metricName turns a performance collector name such as "CPU/Load/User"
+// into a Prometheus metric name component.
+func metricName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '_'
+	}, s)
+}
+
+// labels is a list of alternating label names and values.
+type labels []string
+
+// metricSet groups samples into metric families for exposition.
+type metricSet struct {
+	families map[string]*metricFamily
+}
+
+type metricFamily struct {
+	typ     string
+	help    string
+	samples []string
+}
+
+func newMetricSet() *metricSet {
+	return &metricSet{families: make(map[string]*metricFamily)}
+}
+
+// add adds a sample to the family name. suffix is appended to the sample
+// name, as in the _sum and _count samples of a summary.
+func (ms *metricSet) add(name, typ, help, suffix string, l labels, value float64) {
+	f, ok := ms.families[name]
+	if !ok {
+		f = &metricFamily{typ: typ, help: help}
+		ms.families[name] = f
+	}
+
+	var b strings.Builder
+	b.WriteString(name + suffix)
+	if len(l) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(l); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(l[i] + `="` + escapeLabelValue(l[i+1]) + `"`)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64))
+	f.samples = append(f.samples, b.String())
+}
+
+func (ms *metricSet) writeTo(w io.Writer) {
+	names := make([]string, 0, len(ms.families))
+	for name := range ms.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		f := ms.families[name]
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, f.typ)
+		for _, s := range f.samples {
+			buf.WriteString(s + "\n")
+		}
+	}
+	w.Write(buf.Bytes())
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
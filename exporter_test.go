@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxapi"
+)
+
+func TestMetricName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"CPU/Load/User", "cpu_load_user"},
+		{"RAM/Usage/Used", "ram_usage_used"},
+		{"Net/eth0/Rate/Rx", "net_eth0_rate_rx"},
+		{"Disk/sda:1", "disk_sda_1"},
+	}
+
+	for _, tt := range tests {
+		if got := metricName(tt.name); got != tt.want {
+			t.Errorf("metricName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{`C:\VMs\disk.vdi`, `C:\\VMs\\disk.vdi`},
+		{`say "hi"`, `say \"hi\"`},
+		{"two\nlines", `two\nlines`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLabelValue(tt.value); got != tt.want {
+			t.Errorf("escapeLabelValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMetricSetWriteTo(t *testing.T) {
+	ms := newMetricSet()
+	ms.add("vbox_up", "gauge", "Whether vboxwebsrv is reachable.", "", nil, 1)
+	ms.add("vbox_machine_state", "gauge", "Machine state.", "", labels{"machine", "a"}, 5)
+	ms.add("vbox_machine_state", "gauge", "Machine state.", "", labels{"machine", `b"c`}, 0.5)
+	ms.add("vbox_soap_seconds", "summary", "SOAP call latency.", "_count", labels{"method", "x", "code", "0"}, 3)
+
+	var buf bytes.Buffer
+	ms.writeTo(&buf)
+
+	want := `# HELP vbox_machine_state Machine state.
+# TYPE vbox_machine_state gauge
+vbox_machine_state{machine="a"} 5
+vbox_machine_state{machine="b\"c"} 0.5
+# HELP vbox_soap_seconds SOAP call latency.
+# TYPE vbox_soap_seconds summary
+vbox_soap_seconds_count{method="x",code="0"} 3
+# HELP vbox_up Whether vboxwebsrv is reachable.
+# TYPE vbox_up gauge
+vbox_up 1
+`
+	if got := buf.String(); got != want {
+		t.Errorf("writeTo() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAddPerformanceSamples(t *testing.T) {
+	tests := []struct {
+		sample *vboxapi.MetricSample
+		want   string
+	}{
+		{&vboxapi.MetricSample{Name: "CPU/Load/User", Unit: "%", Scale: 1000, Values: []int32{1500, 2500}}, `vbox_host_cpu_load_user_percent{host="h"} 2.5`},
+		{&vboxapi.MetricSample{Name: "RAM/Usage/Used", Unit: "kB", Scale: 1, Values: []int32{2048}}, `vbox_host_ram_usage_used_kilobytes{host="h"} 2048`},
+		{&vboxapi.MetricSample{Name: "Net/Rate/Rx", Unit: "B/s", Scale: 1, Values: []int32{10}}, `vbox_host_net_rate_rx_bytes_per_second{host="h"} 10`},
+		{&vboxapi.MetricSample{Name: "Guest/Pages", Unit: "pages/s", Scale: 1, Values: []int32{7}}, `vbox_host_guest_pages_pages_s{host="h"} 7`},
+		{&vboxapi.MetricSample{Name: "Count", Scale: 1, Values: []int32{4}}, `vbox_host_count{host="h"} 4`},
+	}
+
+	for _, tt := range tests {
+		ms := newMetricSet()
+		addPerformanceSamples(ms, "vbox_host_", labels{"host", "h"}, []*vboxapi.MetricSample{tt.sample})
+		if len(ms.families) != 1 {
+			t.Errorf("%s: got %d families, want 1", tt.sample.Name, len(ms.families))
+			continue
+		}
+		for _, f := range ms.families {
+			if len(f.samples) != 1 || f.samples[0] != tt.want {
+				t.Errorf("%s: samples = %q, want %q", tt.sample.Name, f.samples, tt.want)
+			}
+		}
+	}
+
+	ms := newMetricSet()
+	addPerformanceSamples(ms, "vbox_host_", nil, []*vboxapi.MetricSample{{Name: "CPU/Load/User", Unit: "%"}})
+	if len(ms.families) != 0 {
+		t.Errorf("sample without values added %d families, want 0", len(ms.families))
+	}
+}
@@ -1,20 +1,41 @@
 package main
 
 import (
+	"flag"
 	"log"
-	"os"
+	"net/http"
 
 	"github.com/blacktop/go-vboxapi/vboxapi"
 )
 
 func main() {
+	listen := flag.String("listen", "", "serve Prometheus metrics on `address`, e.g. :9338")
+	flag.Parse()
+
 	url := "http://127.0.0.1:18083"
-	if len(os.Args) >= 2 {
-		url = os.Args[1]
+	if flag.NArg() >= 1 {
+		url = flag.Arg(0)
 	}
 
 	client := vboxapi.New("", "", url, false, "")
+
+	var soap *soapStats
+	if *listen != "" {
+		soap = newSOAPStats()
+		client.SetCallObserver(soap.observe)
+	}
+
 	if err := client.Logon(); err != nil {
 		log.Fatalf("Unable to log on to vboxweb: %v\n", err)
 	}
+	if *listen == "" {
+		return
+	}
+
+	e, err := newExporter(client, soap)
+	if err != nil {
+		log.Fatalf("Unable to set up metrics: %v\n", err)
+	}
+	http.Handle("/metrics", e)
+	log.Fatal(http.ListenAndServe(*listen, nil))
 }
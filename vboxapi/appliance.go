@@ -0,0 +1,449 @@
+package vboxapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// Appliance is an OVF appliance being imported.
+type Appliance struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// ApplianceOverrides edits the virtual system descriptions of an appliance
+// before it is imported.
+type ApplianceOverrides func(descriptions []*VirtualSystemDescription) error
+
+// CreateAppliance returns a new, empty appliance.
+func (vb *VirtualBox) CreateAppliance() (*Appliance, error) {
+	request := vboxweb.IVirtualBoxcreateAppliance{This: vb.managedObjectId}
+
+	response, err := vb.IVirtualBoxcreateAppliance(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Appliance{virtualbox: vb, managedObjectId: response.Returnval}, nil
+}
+
+// ImportAppliance imports the OVF or OVA file at path. If overrides is not
+// nil it is called with the interpreted virtual system descriptions, whose
+// changes are applied before the import. Use CreateAppliance to read the
+// warnings of the appliance or to pass import options.
+func (vb *VirtualBox) ImportAppliance(ctx context.Context, path string, overrides ApplianceOverrides) ([]*Machine, error) {
+	appliance, err := vb.CreateAppliance()
+	if err != nil {
+		return nil, err
+	}
+	defer appliance.Release()
+
+	if err := appliance.Read(ctx, path); err != nil {
+		return nil, err
+	}
+	if err := appliance.Interpret(); err != nil {
+		return nil, err
+	}
+
+	descriptions, err := appliance.GetVirtualSystemDescriptions()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, d := range descriptions {
+			d.Release()
+		}
+	}()
+
+	if overrides != nil {
+		if err := overrides(descriptions); err != nil {
+			return nil, err
+		}
+		for _, d := range descriptions {
+			if err := d.Apply(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := appliance.ImportMachines(ctx); err != nil {
+		return nil, err
+	}
+
+	ids, err := appliance.GetMachines()
+	if err != nil {
+		return nil, err
+	}
+	machines := make([]*Machine, 0, len(ids))
+	for _, id := range ids {
+		m, err := vb.FindMachine(id)
+		if err != nil {
+			for _, m := range machines {
+				m.Release()
+			}
+			return nil, err
+		}
+		m.ID = id
+		machines = append(machines, m)
+	}
+	return machines, nil
+}
+
+// Read reads the OVF or OVA file at path into the appliance.
+func (a *Appliance) Read(ctx context.Context, path string) error {
+	request := vboxweb.IApplianceread{This: a.managedObjectId, File: path}
+
+	response, err := a.virtualbox.IApplianceread(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	progress := &Progress{virtualbox: a.virtualbox, managedObjectId: response.Returnval}
+	defer progress.Release()
+
+	return progress.Wait(ctx, nil)
+}
+
+// Interpret fills the virtual system descriptions from the appliance read.
+func (a *Appliance) Interpret() error {
+	request := vboxweb.IApplianceinterpret{This: a.managedObjectId}
+
+	_, err := a.virtualbox.IApplianceinterpret(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// GetWarnings returns the warnings raised while interpreting the appliance,
+// such as unsupported hardware that will not be imported.
+func (a *Appliance) GetWarnings() ([]string, error) {
+	request := vboxweb.IAppliancegetWarnings{This: a.managedObjectId}
+
+	response, err := a.virtualbox.IAppliancegetWarnings(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GetVirtualSystemDescriptions returns a description per virtual system of
+// the interpreted appliance.
+func (a *Appliance) GetVirtualSystemDescriptions() ([]*VirtualSystemDescription, error) {
+	request := vboxweb.IAppliancegetVirtualSystemDescriptions{This: a.managedObjectId}
+
+	response, err := a.virtualbox.IAppliancegetVirtualSystemDescriptions(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	descriptions := make([]*VirtualSystemDescription, len(response.Returnval))
+	for i, moid := range response.Returnval {
+		descriptions[i] = &VirtualSystemDescription{virtualbox: a.virtualbox, managedObjectId: moid}
+	}
+	for _, d := range descriptions {
+		if err := d.Refresh(); err != nil {
+			for _, d := range descriptions {
+				d.Release()
+			}
+			return nil, err
+		}
+	}
+	return descriptions, nil
+}
+
+// ImportMachines creates the machines described by the appliance.
+func (a *Appliance) ImportMachines(ctx context.Context, options ...vboxweb.ImportOptions) error {
+	opts := make([]*vboxweb.ImportOptions, len(options))
+	for i := range options {
+		opts[i] = &options[i]
+	}
+	request := vboxweb.IApplianceimportMachines{This: a.managedObjectId, Options: opts}
+
+	response, err := a.virtualbox.IApplianceimportMachines(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	progress := &Progress{virtualbox: a.virtualbox, managedObjectId: response.Returnval}
+	defer progress.Release()
+
+	return progress.Wait(ctx, nil)
+}
+
+// GetMachines returns the IDs of the machines created by ImportMachines.
+func (a *Appliance) GetMachines() ([]string, error) {
+	request := vboxweb.IAppliancegetMachines{This: a.managedObjectId}
+
+	response, err := a.virtualbox.IAppliancegetMachines(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// Release releases the managed object reference of the appliance.
+func (a *Appliance) Release() error {
+	return a.virtualbox.Release(a.managedObjectId)
+}
+
+// VirtualSystemDescriptionEntry is a setting of a virtual system to be
+// imported. VBoxValue is the value VirtualBox suggests and may be edited;
+// disabled entries are not imported.
+type VirtualSystemDescriptionEntry struct {
+	Type        vboxweb.VirtualSystemDescriptionType
+	Ref         string
+	OVFValue    string
+	VBoxValue   string
+	ExtraConfig string
+	Enabled     bool
+}
+
+// ApplianceDisk is a hard disk image of a virtual system to be imported.
+type ApplianceDisk struct {
+	// Entry is the description entry the disk was read from.
+	Entry *VirtualSystemDescriptionEntry
+	// Source is the disk image in the appliance.
+	Source string
+	// Path is where the disk image is created.
+	Path string
+	// ControllerRef is the Ref of the controller entry the disk is attached
+	// to, Controller its type, and Channel the port on it. Controller is
+	// only informational; Apply uses ControllerRef.
+	ControllerRef string
+	Controller    vboxweb.VirtualSystemDescriptionType
+	Channel       int
+}
+
+// ApplianceNIC is a network adapter of a virtual system to be imported.
+type ApplianceNIC struct {
+	// Entry is the description entry the adapter was read from.
+	Entry *VirtualSystemDescriptionEntry
+	// Network is the network the adapter is connected to in the appliance.
+	Network string
+	// AdapterType is the emulated network card.
+	AdapterType vboxweb.NetworkAdapterType
+	// Attachment is how the adapter is attached, such as "NAT" or
+	// "Bridged".
+	Attachment string
+}
+
+// networkAdapterTypes lists the network adapter types in the order of their
+// numeric values, as used in description entries.
+var networkAdapterTypes = []vboxweb.NetworkAdapterType{
+	vboxweb.NetworkAdapterTypeNull,
+	vboxweb.NetworkAdapterTypeAm79C970A,
+	vboxweb.NetworkAdapterTypeAm79C973,
+	vboxweb.NetworkAdapterTypeI82540EM,
+	vboxweb.NetworkAdapterTypeI82543GC,
+	vboxweb.NetworkAdapterTypeI82545EM,
+	vboxweb.NetworkAdapterTypeVirtio,
+}
+
+// VirtualSystemDescription describes a virtual system of an appliance. The
+// typed fields mirror the matching entries; Apply writes changes to either
+// back to VirtualBox.
+type VirtualSystemDescription struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+
+	Entries  []*VirtualSystemDescriptionEntry
+	Name     string
+	OSType   string
+	CPUs     uint32
+	MemoryMB uint64
+	Disks    []*ApplianceDisk
+	NICs     []*ApplianceNIC
+}
+
+// Refresh reads the entries of the description and fills the typed fields.
+func (d *VirtualSystemDescription) Refresh() error {
+	request := vboxweb.IVirtualSystemDescriptiongetDescription{This: d.managedObjectId}
+
+	response, err := d.virtualbox.IVirtualSystemDescriptiongetDescription(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	d.Entries = make([]*VirtualSystemDescriptionEntry, len(response.Types))
+	d.Name, d.OSType, d.CPUs, d.MemoryMB = "", "", 0, 0
+	d.Disks, d.NICs = nil, nil
+	for i, t := range response.Types {
+		e := &VirtualSystemDescriptionEntry{
+			Ref:         stringAt(response.Refs, i),
+			OVFValue:    stringAt(response.OVFValues, i),
+			VBoxValue:   stringAt(response.VBoxValues, i),
+			ExtraConfig: stringAt(response.ExtraConfigValues, i),
+			Enabled:     true,
+		}
+		if t != nil {
+			e.Type = *t
+		}
+		d.Entries[i] = e
+
+		switch e.Type {
+		case vboxweb.VirtualSystemDescriptionTypeName:
+			d.Name = e.VBoxValue
+		case vboxweb.VirtualSystemDescriptionTypeOS:
+			d.OSType = e.VBoxValue
+		case vboxweb.VirtualSystemDescriptionTypeCPU:
+			cpus, _ := strconv.ParseUint(e.VBoxValue, 10, 32)
+			d.CPUs = uint32(cpus)
+		case vboxweb.VirtualSystemDescriptionTypeMemory:
+			d.MemoryMB, _ = strconv.ParseUint(e.VBoxValue, 10, 64)
+		case vboxweb.VirtualSystemDescriptionTypeHardDiskImage:
+			d.Disks = append(d.Disks, newApplianceDisk(e))
+		case vboxweb.VirtualSystemDescriptionTypeNetworkAdapter:
+			d.NICs = append(d.NICs, newApplianceNIC(e))
+		}
+	}
+
+	for _, disk := range d.Disks {
+		for _, e := range d.Entries {
+			if e.Ref == disk.ControllerRef && isDiskController(e.Type) {
+				disk.Controller = e.Type
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func isDiskController(t vboxweb.VirtualSystemDescriptionType) bool {
+	switch t {
+	case vboxweb.VirtualSystemDescriptionTypeHardDiskControllerIDE,
+		vboxweb.VirtualSystemDescriptionTypeHardDiskControllerSATA,
+		vboxweb.VirtualSystemDescriptionTypeHardDiskControllerSCSI,
+		vboxweb.VirtualSystemDescriptionTypeHardDiskControllerSAS:
+		return true
+	}
+	return false
+}
+
+func newApplianceDisk(e *VirtualSystemDescriptionEntry) *ApplianceDisk {
+	disk := &ApplianceDisk{Entry: e, Source: e.OVFValue, Path: e.VBoxValue}
+	disk.ControllerRef, _ = extraConfigValue(e.ExtraConfig, "controller")
+	if v, ok := extraConfigValue(e.ExtraConfig, "channel"); ok {
+		disk.Channel, _ = strconv.Atoi(v)
+	}
+	return disk
+}
+
+func newApplianceNIC(e *VirtualSystemDescriptionEntry) *ApplianceNIC {
+	nic := &ApplianceNIC{Entry: e, Network: e.OVFValue}
+	if i, err := strconv.Atoi(e.VBoxValue); err == nil && i >= 0 && i < len(networkAdapterTypes) {
+		nic.AdapterType = networkAdapterTypes[i]
+	}
+	nic.Attachment, _ = extraConfigValue(e.ExtraConfig, "type")
+	return nic
+}
+
+// apply copies the typed fields of the disk into its entry.
+func (disk *ApplianceDisk) apply() {
+	disk.Entry.VBoxValue = disk.Path
+	if disk.ControllerRef != "" {
+		disk.Entry.ExtraConfig = setExtraConfigValue(disk.Entry.ExtraConfig, "controller", disk.ControllerRef)
+		disk.Entry.ExtraConfig = setExtraConfigValue(disk.Entry.ExtraConfig, "channel", strconv.Itoa(disk.Channel))
+	}
+}
+
+// apply copies the typed fields of the adapter into its entry.
+func (nic *ApplianceNIC) apply() {
+	for i, t := range networkAdapterTypes {
+		if t == nic.AdapterType {
+			nic.Entry.VBoxValue = strconv.Itoa(i)
+			break
+		}
+	}
+	if nic.Attachment != "" {
+		nic.Entry.ExtraConfig = setExtraConfigValue(nic.Entry.ExtraConfig, "type", nic.Attachment)
+	}
+}
+
+// Apply copies the typed fields into their entries and sets the entries as
+// the final values to import. Typed fields without a matching entry are
+// ignored.
+func (d *VirtualSystemDescription) Apply() error {
+	for _, disk := range d.Disks {
+		disk.apply()
+	}
+	for _, nic := range d.NICs {
+		nic.apply()
+	}
+
+	n := len(d.Entries)
+	request := vboxweb.IVirtualSystemDescriptionsetFinalValues{
+		This:              d.managedObjectId,
+		Enabled:           make([]bool, n),
+		VBoxValues:        make([]string, n),
+		ExtraConfigValues: make([]string, n),
+	}
+
+	for i, e := range d.Entries {
+		switch e.Type {
+		case vboxweb.VirtualSystemDescriptionTypeName:
+			e.VBoxValue = d.Name
+		case vboxweb.VirtualSystemDescriptionTypeOS:
+			e.VBoxValue = d.OSType
+		case vboxweb.VirtualSystemDescriptionTypeCPU:
+			e.VBoxValue = strconv.FormatUint(uint64(d.CPUs), 10)
+		case vboxweb.VirtualSystemDescriptionTypeMemory:
+			e.VBoxValue = strconv.FormatUint(d.MemoryMB, 10)
+		}
+		request.Enabled[i] = e.Enabled
+		request.VBoxValues[i] = e.VBoxValue
+		request.ExtraConfigValues[i] = e.ExtraConfig
+	}
+
+	_, err := d.virtualbox.IVirtualSystemDescriptionsetFinalValues(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Release releases the managed object reference of the description.
+func (d *VirtualSystemDescription) Release() error {
+	return d.virtualbox.Release(d.managedObjectId)
+}
+
+func stringAt(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+// extraConfigValue returns the value of key in the "key=value;key=value"
+// extra configuration of a description entry.
+func extraConfigValue(config, key string) (string, bool) {
+	for _, kv := range strings.Split(config, ";") {
+		if i := strings.IndexByte(kv, '='); i >= 0 && strings.TrimSpace(kv[:i]) == key {
+			return strings.TrimSpace(kv[i+1:]), true
+		}
+	}
+	return "", false
+}
+
+// setExtraConfigValue sets key to value in the extra configuration config,
+// keeping the other pairs.
+func setExtraConfigValue(config, key, value string) string {
+	var pairs []string
+	if config != "" {
+		pairs = strings.Split(config, ";")
+	}
+	for i, kv := range pairs {
+		if eq := strings.IndexByte(kv, '='); eq >= 0 && strings.TrimSpace(kv[:eq]) == key {
+			pairs[i] = key + "=" + value
+			return strings.Join(pairs, ";")
+		}
+	}
+	return strings.Join(append(pairs, key+"="+value), ";")
+}
@@ -0,0 +1,131 @@
+package vboxapi
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestExtraConfigValue(t *testing.T) {
+	tests := []struct {
+		config, key string
+		want        string
+		wantOK      bool
+	}{
+		{"controller=5;channel=1", "controller", "5", true},
+		{"controller=5;channel=1", "channel", "1", true},
+		{"slot=0; type=Bridged", "type", "Bridged", true},
+		{"type=NAT", "slot", "", false},
+		{"", "type", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := extraConfigValue(tt.config, tt.key)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("extraConfigValue(%q, %q) = %q, %v, want %q, %v", tt.config, tt.key, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSetExtraConfigValue(t *testing.T) {
+	tests := []struct {
+		config, key, value string
+		want               string
+	}{
+		{"controller=5;channel=1", "channel", "0", "controller=5;channel=0"},
+		{"slot=0", "type", "NAT", "slot=0;type=NAT"},
+		{"", "type", "HostOnly", "type=HostOnly"},
+	}
+
+	for _, tt := range tests {
+		if got := setExtraConfigValue(tt.config, tt.key, tt.value); got != tt.want {
+			t.Errorf("setExtraConfigValue(%q, %q, %q) = %q, want %q", tt.config, tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+// applianceDescription is the getDescription response of a virtual system
+// with a SATA disk and a NAT Intel PRO/1000 MT Desktop adapter.
+const applianceDescription = `
+<types>Name</types><refs></refs><OVFValues>vm</OVFValues><VBoxValues>vm</VBoxValues><extraConfigValues></extraConfigValues>
+<types>CPU</types><refs></refs><OVFValues>1</OVFValues><VBoxValues>1</VBoxValues><extraConfigValues></extraConfigValues>
+<types>Memory</types><refs></refs><OVFValues>1024</OVFValues><VBoxValues>1024</VBoxValues><extraConfigValues></extraConfigValues>
+<types>HardDiskControllerSATA</types><refs>5</refs><OVFValues>AHCI</OVFValues><VBoxValues>AHCI</VBoxValues><extraConfigValues></extraConfigValues>
+<types>HardDiskImage</types><refs>disk1</refs><OVFValues>vm-disk1.vmdk</OVFValues><VBoxValues>/vms/vm/disk1.vmdk</VBoxValues><extraConfigValues>controller=5;channel=0</extraConfigValues>
+<types>NetworkAdapter</types><refs></refs><OVFValues>NAT</OVFValues><VBoxValues>3</VBoxValues><extraConfigValues>slot=0;type=NAT</extraConfigValues>`
+
+func TestVirtualSystemDescriptionRefresh(t *testing.T) {
+	f := newFakeVBox(t)
+	f.respond("IVirtualSystemDescription_getDescription", applianceDescription)
+	d := &VirtualSystemDescription{virtualbox: f.VirtualBox, managedObjectId: "description"}
+
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Name != "vm" || d.CPUs != 1 || d.MemoryMB != 1024 {
+		t.Errorf("Name, CPUs, MemoryMB = %q, %d, %d, want \"vm\", 1, 1024", d.Name, d.CPUs, d.MemoryMB)
+	}
+	if len(d.Disks) != 1 {
+		t.Fatalf("got %d disks, want 1", len(d.Disks))
+	}
+	disk := d.Disks[0]
+	if disk.Source != "vm-disk1.vmdk" || disk.Path != "/vms/vm/disk1.vmdk" {
+		t.Errorf("disk Source, Path = %q, %q", disk.Source, disk.Path)
+	}
+	if disk.ControllerRef != "5" || disk.Controller != vboxweb.VirtualSystemDescriptionTypeHardDiskControllerSATA || disk.Channel != 0 {
+		t.Errorf("disk ControllerRef, Controller, Channel = %q, %q, %d", disk.ControllerRef, disk.Controller, disk.Channel)
+	}
+	if len(d.NICs) != 1 {
+		t.Fatalf("got %d NICs, want 1", len(d.NICs))
+	}
+	nic := d.NICs[0]
+	if nic.AdapterType != vboxweb.NetworkAdapterTypeI82540EM || nic.Attachment != "NAT" {
+		t.Errorf("NIC AdapterType, Attachment = %q, %q, want I82540EM, NAT", nic.AdapterType, nic.Attachment)
+	}
+}
+
+func TestVirtualSystemDescriptionApply(t *testing.T) {
+	f := newFakeVBox(t)
+	f.respond("IVirtualSystemDescription_getDescription", applianceDescription)
+	d := &VirtualSystemDescription{virtualbox: f.VirtualBox, managedObjectId: "description"}
+	if err := d.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+
+	d.Name = "copy"
+	d.CPUs = 2
+	d.Disks[0].Path = "/vms/copy/disk1.vmdk"
+	d.Disks[0].Channel = 3
+	d.NICs[0].AdapterType = vboxweb.NetworkAdapterTypeVirtio
+	d.NICs[0].Attachment = "Bridged"
+	d.Entries[1].Enabled = false
+
+	if err := d.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := f.requests("IVirtualSystemDescription_setFinalValues")
+	if len(reqs) != 1 {
+		t.Fatalf("got %d setFinalValues requests, want 1", len(reqs))
+	}
+	var req vboxweb.IVirtualSystemDescriptionsetFinalValues
+	if err := xml.Unmarshal(reqs[0], &req); err != nil {
+		t.Fatal(err)
+	}
+
+	wantEnabled := []bool{true, false, true, true, true, true}
+	if !reflect.DeepEqual(req.Enabled, wantEnabled) {
+		t.Errorf("Enabled = %v, want %v", req.Enabled, wantEnabled)
+	}
+	wantValues := []string{"copy", "2", "1024", "AHCI", "/vms/copy/disk1.vmdk", "6"}
+	if !reflect.DeepEqual(req.VBoxValues, wantValues) {
+		t.Errorf("VBoxValues = %q, want %q", req.VBoxValues, wantValues)
+	}
+	wantExtra := []string{"", "", "", "", "controller=5;channel=3", "slot=0;type=Bridged"}
+	if !reflect.DeepEqual(req.ExtraConfigValues, wantExtra) {
+		t.Errorf("ExtraConfigValues = %q, want %q", req.ExtraConfigValues, wantExtra)
+	}
+}
@@ -0,0 +1,46 @@
+package vboxapi
+
+import "github.com/blacktop/go-vboxapi/vboxweb"
+
+// BandwidthControl is a VirtualBox bandwidth control object, holding the
+// bandwidth groups of a machine.
+type BandwidthControl struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// BandwidthGroup is a VirtualBox bandwidth group object.
+type BandwidthGroup struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+func (bc *BandwidthControl) GetBandwidthGroup(name string) (*BandwidthGroup, error) {
+	request := vboxweb.IBandwidthControlgetBandwidthGroup{This: bc.managedObjectId, Name: name}
+
+	response, err := bc.virtualbox.IBandwidthControlgetBandwidthGroup(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &BandwidthGroup{virtualbox: bc.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+func (bc *BandwidthControl) Release() error {
+	return bc.virtualbox.Release(bc.managedObjectId)
+}
+
+func (bg *BandwidthGroup) GetName() (string, error) {
+	request := vboxweb.IBandwidthGroupgetName{This: bg.managedObjectId}
+
+	response, err := bg.virtualbox.IBandwidthGroupgetName(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (bg *BandwidthGroup) Release() error {
+	return bg.virtualbox.Release(bg.managedObjectId)
+}
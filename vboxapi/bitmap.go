@@ -0,0 +1,43 @@
+package vboxapi
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// decodeBitmap converts a bitmap returned by VirtualBox into an image.
+// width and height are only used for the raw pixel formats.
+func decodeBitmap(format vboxweb.BitmapFormat, width, height uint32, data []byte) (image.Image, error) {
+	if format == vboxweb.BitmapFormatPNG {
+		return png.Decode(bytes.NewReader(data))
+	}
+
+	bpp := 4
+	if format == vboxweb.BitmapFormatBGR {
+		bpp = 3
+	}
+	w, h := int(width), int(height)
+	if len(data) < w*h*bpp {
+		return nil, fmt.Errorf("short %s bitmap: got %d bytes for %dx%d", format, len(data), w, h)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i, o := 0, 0; i < w*h; i, o = i+1, o+bpp {
+		px := img.Pix[i*4 : i*4+4]
+		switch format {
+		case vboxweb.BitmapFormatRGBA:
+			copy(px, data[o:o+4])
+		case vboxweb.BitmapFormatBGRA:
+			px[0], px[1], px[2], px[3] = data[o+2], data[o+1], data[o], data[o+3]
+		case vboxweb.BitmapFormatBGR0, vboxweb.BitmapFormatBGR:
+			px[0], px[1], px[2], px[3] = data[o+2], data[o+1], data[o], 0xff
+		default:
+			return nil, fmt.Errorf("unsupported bitmap format %s", format)
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,60 @@
+package vboxapi
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestDecodeBitmap(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	blue := color.RGBA{B: 0x80, A: 0x80}
+
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, red)
+	src.Set(1, 0, blue)
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, src); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format  vboxweb.BitmapFormat
+		data    []byte
+		want    []color.RGBA
+		wantErr bool
+	}{
+		{vboxweb.BitmapFormatRGBA, []byte{0xff, 0, 0, 0xff, 0, 0, 0x80, 0x80}, []color.RGBA{red, blue}, false},
+		{vboxweb.BitmapFormatBGRA, []byte{0, 0, 0xff, 0xff, 0x80, 0, 0, 0x80}, []color.RGBA{red, blue}, false},
+		{vboxweb.BitmapFormatBGR0, []byte{0, 0, 0xff, 0, 0xff, 0, 0, 0}, []color.RGBA{red, {B: 0xff, A: 0xff}}, false},
+		{vboxweb.BitmapFormatBGR, []byte{0, 0, 0xff, 0xff, 0, 0}, []color.RGBA{red, {B: 0xff, A: 0xff}}, false},
+		{vboxweb.BitmapFormatPNG, pngData.Bytes(), []color.RGBA{red, blue}, false},
+		{vboxweb.BitmapFormatRGBA, []byte{0xff, 0, 0, 0xff}, nil, true},
+		{vboxweb.BitmapFormatJPEG, make([]byte, 8), nil, true},
+	}
+
+	for _, tt := range tests {
+		img, err := decodeBitmap(tt.format, 2, 1, tt.data)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decodeBitmap(%s) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if b := img.Bounds(); b.Dx() != 2 || b.Dy() != 1 {
+			t.Errorf("decodeBitmap(%s) bounds = %v, want 2x1", tt.format, b)
+			continue
+		}
+		for x, want := range tt.want {
+			got := color.NRGBAModel.Convert(img.At(x, 0))
+			if got != color.NRGBAModel.Convert(want) {
+				t.Errorf("decodeBitmap(%s) pixel %d = %v, want %v", tt.format, x, got, want)
+			}
+		}
+	}
+}
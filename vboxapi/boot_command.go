@@ -0,0 +1,156 @@
+package vboxapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bootStep is a single step of a parsed boot command. Exactly one of text,
+// key, hold or wait is set.
+type bootStep struct {
+	text    string
+	key     KeyCode
+	hasKey  bool
+	hold    KeyCode
+	holdOn  bool
+	hasHold bool
+	wait    time.Duration
+}
+
+// holdKeys are the modifiers that can be held with <nameOn> and released
+// with <nameOff> in boot commands.
+var holdKeys = map[string]KeyCode{
+	"leftalt":    keyLeftAlt,
+	"rightalt":   keyRightAlt,
+	"leftctrl":   keyLeftCtrl,
+	"rightctrl":  keyRightCtrl,
+	"leftshift":  keyLeftShift,
+	"rightshift": keyRightShift,
+	"leftsuper":  keyLeftSuper,
+	"rightsuper": keyRightSuper,
+}
+
+// parseBootTag parses the contents of a <tag> in a boot command.
+// It returns false if tag is not a known special key.
+func parseBootTag(tag string) (bootStep, bool) {
+	lower := strings.ToLower(tag)
+
+	if strings.HasPrefix(lower, "wait") {
+		arg := lower[len("wait"):]
+		if arg == "" {
+			return bootStep{wait: time.Second}, true
+		}
+		if n, err := strconv.Atoi(arg); err == nil && n >= 0 {
+			return bootStep{wait: time.Duration(n) * time.Second}, true
+		}
+		if d, err := time.ParseDuration(arg); err == nil && d >= 0 {
+			return bootStep{wait: d}, true
+		}
+		return bootStep{}, false
+	}
+
+	for name, code := range holdKeys {
+		switch lower {
+		case name + "on":
+			return bootStep{hold: code, holdOn: true, hasHold: true}, true
+		case name + "off":
+			return bootStep{hold: code, hasHold: true}, true
+		}
+	}
+
+	if code, ok := namedKeys[lower]; ok {
+		return bootStep{key: code, hasKey: true}, true
+	}
+	return bootStep{}, false
+}
+
+// parseBootCommand splits a boot command into steps. Text outside of known
+// <tags> is typed literally, including the brackets of unknown tags.
+func parseBootCommand(command string) []bootStep {
+	var steps []bootStep
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			steps = append(steps, bootStep{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for len(command) > 0 {
+		if command[0] == '<' {
+			if end := strings.IndexByte(command, '>'); end > 0 {
+				if step, ok := parseBootTag(command[1:end]); ok {
+					flush()
+					steps = append(steps, step)
+					command = command[end+1:]
+					continue
+				}
+			}
+		}
+		text.WriteByte(command[0])
+		command = command[1:]
+	}
+	flush()
+
+	return steps
+}
+
+// RunBootCommand types a boot command in the style of Packer's
+// boot_command. Special keys are written as tags such as <enter>, <esc>,
+// <f2> or <pageUp>; <wait>, <wait5> and <wait1m30s> pause for one second,
+// five seconds and the given duration; <leftAltOn> and <leftAltOff> hold
+// and release a modifier. All other text is typed literally. Held modifiers
+// are released when the command ends or ctx is done.
+func (k *Keyboard) RunBootCommand(ctx context.Context, command string) error {
+	steps := parseBootCommand(command)
+
+	held := make(map[KeyCode]bool)
+	defer func() {
+		for code := range held {
+			k.put(context.Background(), code.breakCodes())
+		}
+	}()
+
+	for _, step := range steps {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		switch {
+		case step.wait > 0:
+			timer := time.NewTimer(step.wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		case step.hasKey:
+			codes := append(step.key.makeCodes(), step.key.breakCodes()...)
+			if err := k.put(ctx, codes); err != nil {
+				return err
+			}
+		case step.hasHold && step.holdOn:
+			if err := k.put(ctx, step.hold.makeCodes()); err != nil {
+				return err
+			}
+			held[step.hold] = true
+		case step.hasHold:
+			if err := k.put(ctx, step.hold.breakCodes()); err != nil {
+				return err
+			}
+			delete(held, step.hold)
+		case step.text != "":
+			if err := k.Type(ctx, step.text); err != nil {
+				return fmt.Errorf("boot command: %v", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package vboxapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseBootCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []bootStep
+	}{
+		{"", nil},
+		{"linux text", []bootStep{{text: "linux text"}}},
+		{
+			"<esc><wait>linux<enter>",
+			[]bootStep{
+				{key: keyEsc, hasKey: true},
+				{wait: time.Second},
+				{text: "linux"},
+				{key: keyEnter, hasKey: true},
+			},
+		},
+		{
+			"<wait5><wait1m30s><WAIT10>",
+			[]bootStep{{wait: 5 * time.Second}, {wait: 90 * time.Second}, {wait: 10 * time.Second}},
+		},
+		{
+			"<leftAltOn>f<leftAltOff>",
+			[]bootStep{
+				{hold: keyLeftAlt, holdOn: true, hasHold: true},
+				{text: "f"},
+				{hold: keyLeftAlt, hasHold: true},
+			},
+		},
+		{"a<b>c<F2>", []bootStep{{text: "a<b>c"}, {key: keyF1 + 1, hasKey: true}}},
+		{"x < y <pageUp", []bootStep{{text: "x < y <pageUp"}}},
+		{"<wait-1>", []bootStep{{text: "<wait-1>"}}},
+	}
+
+	for _, tt := range tests {
+		if got := parseBootCommand(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseBootCommand(%q) = %+v, want %+v", tt.command, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package vboxapi
 
-import "github.com/blacktop/go-vboxapi/vboxweb"
+import (
+	"context"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
 
 // Console is a VirtualBox console object
 type Console struct {
@@ -34,6 +38,43 @@ func (c *Console) PowerUp() (*Progress, error) {
 	return &Progress{virtualbox: c.virtualbox, managedObjectId: response.Returnval}, nil
 }
 
+// GetEventSource returns the event source of the console, which delivers
+// VM events such as state changes, runtime errors and USB attach.
+func (c *Console) GetEventSource() (*EventSource, error) {
+	request := vboxweb.IConsolegetEventSource{This: c.managedObjectID}
+
+	response, err := c.virtualbox.IConsolegetEventSource(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &EventSource{virtualbox: c.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// Events subscribes to the event source of the console. See
+// EventSource.Events.
+func (c *Console) Events(ctx context.Context, types ...vboxweb.VBoxEventType) (<-chan Event, error) {
+	es, err := c.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.events(ctx, true, types)
+}
+
+// HandleVetoable registers fn on the console's event source for requests
+// to show the VM window. See EventSource.HandleVetoable.
+func (c *Console) HandleVetoable(ctx context.Context, fn VetoFunc) (<-chan error, error) {
+	es, err := c.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.handleVetoable(ctx, true, fn, []vboxweb.VBoxEventType{vboxweb.VBoxEventTypeOnCanShowWindow})
+}
+
+func (c *Console) Release() error {
+	return c.virtualbox.Release(c.managedObjectID)
+}
+
 // func (console *Console) PowerDown() (Progress, error) {
 // 	var progress Progress
 // 	result := C.GoVboxConsolePowerDown(console.cconsole, &progress.cprogress)
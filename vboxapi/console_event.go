@@ -0,0 +1,431 @@
+package vboxapi
+
+import (
+	"encoding/base64"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// StateChangedEvent is delivered by a console when its VM changes state.
+type StateChangedEvent struct {
+	BaseEvent
+	State vboxweb.MachineState
+}
+
+// RuntimeErrorEvent reports a runtime error of a VM, such as a full host
+// disk. A fatal error leaves the VM paused.
+type RuntimeErrorEvent struct {
+	BaseEvent
+	Fatal   bool
+	ID      string
+	Message string
+}
+
+// USBDeviceStateChangedEvent reports a USB device attached to or detached from a VM.
+type USBDeviceStateChangedEvent struct {
+	BaseEvent
+	Attached bool
+	Error    string
+}
+
+// KeyboardLedsChangedEvent reports the new state of the guest keyboard LEDs.
+type KeyboardLedsChangedEvent struct {
+	BaseEvent
+	NumLock    bool
+	CapsLock   bool
+	ScrollLock bool
+}
+
+// GuestKeyboardEvent carries scancodes sent to the guest keyboard.
+type GuestKeyboardEvent struct {
+	BaseEvent
+	Scancodes []int32
+}
+
+// MousePointerShapeChangedEvent carries the new shape of the guest mouse pointer.
+type MousePointerShapeChangedEvent struct {
+	BaseEvent
+	Visible bool
+	Alpha   bool
+	XHot    uint32
+	YHot    uint32
+	Width   uint32
+	Height  uint32
+	Shape   []byte
+}
+
+// MouseCapabilityChangedEvent reports which mouse input modes the guest supports.
+type MouseCapabilityChangedEvent struct {
+	BaseEvent
+	SupportsAbsolute   bool
+	SupportsRelative   bool
+	SupportsMultiTouch bool
+	NeedsHostCursor    bool
+}
+
+// GuestSessionStateChangedEvent is delivered when a guest session changes status.
+type GuestSessionStateChangedEvent struct {
+	BaseEvent
+	ID     uint32
+	Status vboxweb.GuestSessionStatus
+	Error  string
+}
+
+// GuestProcessStateChangedEvent is delivered when a guest process changes status.
+type GuestProcessStateChangedEvent struct {
+	BaseEvent
+	PID    uint32
+	Status vboxweb.ProcessStatus
+	Error  string
+}
+
+// GuestProcessOutputEvent carries output of a guest process on the given
+// handle, 1 for stdout and 2 for stderr.
+type GuestProcessOutputEvent struct {
+	BaseEvent
+	PID    uint32
+	Handle uint32
+	Data   []byte
+}
+
+// GuestFileStateChangedEvent is delivered when a guest file changes status.
+type GuestFileStateChangedEvent struct {
+	BaseEvent
+	Status vboxweb.FileStatus
+	Error  string
+}
+
+// GuestFileIOEvent is delivered for guest file reads, writes and offset
+// changes; Type tells which.
+type GuestFileIOEvent struct {
+	BaseEvent
+	Offset    int64
+	Processed uint32
+}
+
+func decodeStateChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	request := vboxweb.IStateChangedEventgetState{This: moid}
+
+	response, err := vb.IStateChangedEventgetState(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	ev := &StateChangedEvent{BaseEvent: base}
+	if response.Returnval != nil {
+		ev.State = *response.Returnval
+	}
+	return ev, nil
+}
+
+func decodeRuntimeError(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	fatalRequest := vboxweb.IRuntimeErrorEventgetFatal{This: moid}
+	fatalResponse, err := vb.IRuntimeErrorEventgetFatal(&fatalRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	idRequest := vboxweb.IRuntimeErrorEventgetId{This: moid}
+	idResponse, err := vb.IRuntimeErrorEventgetId(&idRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	messageRequest := vboxweb.IRuntimeErrorEventgetMessage{This: moid}
+	messageResponse, err := vb.IRuntimeErrorEventgetMessage(&messageRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &RuntimeErrorEvent{
+		BaseEvent: base,
+		Fatal:     fatalResponse.Returnval,
+		ID:        idResponse.Returnval,
+		Message:   messageResponse.Returnval,
+	}, nil
+}
+
+func decodeUSBDeviceStateChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	attachedRequest := vboxweb.IUSBDeviceStateChangedEventgetAttached{This: moid}
+	attachedResponse, err := vb.IUSBDeviceStateChangedEventgetAttached(&attachedRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	errorRequest := vboxweb.IUSBDeviceStateChangedEventgetError{This: moid}
+	errorResponse, err := vb.IUSBDeviceStateChangedEventgetError(&errorRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	text, err := errorInfoText(vb, errorResponse.Returnval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &USBDeviceStateChangedEvent{BaseEvent: base, Attached: attachedResponse.Returnval, Error: text}, nil
+}
+
+func decodeKeyboardLedsChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	numRequest := vboxweb.IKeyboardLedsChangedEventgetNumLock{This: moid}
+	numResponse, err := vb.IKeyboardLedsChangedEventgetNumLock(&numRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	capsRequest := vboxweb.IKeyboardLedsChangedEventgetCapsLock{This: moid}
+	capsResponse, err := vb.IKeyboardLedsChangedEventgetCapsLock(&capsRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	scrollRequest := vboxweb.IKeyboardLedsChangedEventgetScrollLock{This: moid}
+	scrollResponse, err := vb.IKeyboardLedsChangedEventgetScrollLock(&scrollRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &KeyboardLedsChangedEvent{
+		BaseEvent:  base,
+		NumLock:    numResponse.Returnval,
+		CapsLock:   capsResponse.Returnval,
+		ScrollLock: scrollResponse.Returnval,
+	}, nil
+}
+
+func decodeGuestKeyboard(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	request := vboxweb.IGuestKeyboardEventgetScancodes{This: moid}
+
+	response, err := vb.IGuestKeyboardEventgetScancodes(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &GuestKeyboardEvent{BaseEvent: base, Scancodes: response.Returnval}, nil
+}
+
+func decodeMousePointerShapeChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	ev := &MousePointerShapeChangedEvent{BaseEvent: base}
+
+	visibleRequest := vboxweb.IMousePointerShapeChangedEventgetVisible{This: moid}
+	visibleResponse, err := vb.IMousePointerShapeChangedEventgetVisible(&visibleRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	ev.Visible = visibleResponse.Returnval
+
+	alphaRequest := vboxweb.IMousePointerShapeChangedEventgetAlpha{This: moid}
+	alphaResponse, err := vb.IMousePointerShapeChangedEventgetAlpha(&alphaRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	ev.Alpha = alphaResponse.Returnval
+
+	xhotRequest := vboxweb.IMousePointerShapeChangedEventgetXhot{This: moid}
+	xhotResponse, err := vb.IMousePointerShapeChangedEventgetXhot(&xhotRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	ev.XHot = xhotResponse.Returnval
+
+	yhotRequest := vboxweb.IMousePointerShapeChangedEventgetYhot{This: moid}
+	yhotResponse, err := vb.IMousePointerShapeChangedEventgetYhot(&yhotRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	ev.YHot = yhotResponse.Returnval
+
+	widthRequest := vboxweb.IMousePointerShapeChangedEventgetWidth{This: moid}
+	widthResponse, err := vb.IMousePointerShapeChangedEventgetWidth(&widthRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	ev.Width = widthResponse.Returnval
+
+	heightRequest := vboxweb.IMousePointerShapeChangedEventgetHeight{This: moid}
+	heightResponse, err := vb.IMousePointerShapeChangedEventgetHeight(&heightRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	ev.Height = heightResponse.Returnval
+
+	shapeRequest := vboxweb.IMousePointerShapeChangedEventgetShape{This: moid}
+	shapeResponse, err := vb.IMousePointerShapeChangedEventgetShape(&shapeRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	ev.Shape, err = base64.StdEncoding.DecodeString(shapeResponse.Returnval)
+	if err != nil {
+		return nil, err
+	}
+
+	return ev, nil
+}
+
+func decodeMouseCapabilityChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	absoluteRequest := vboxweb.IMouseCapabilityChangedEventgetSupportsAbsolute{This: moid}
+	absoluteResponse, err := vb.IMouseCapabilityChangedEventgetSupportsAbsolute(&absoluteRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	relativeRequest := vboxweb.IMouseCapabilityChangedEventgetSupportsRelative{This: moid}
+	relativeResponse, err := vb.IMouseCapabilityChangedEventgetSupportsRelative(&relativeRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	multiTouchRequest := vboxweb.IMouseCapabilityChangedEventgetSupportsMultiTouch{This: moid}
+	multiTouchResponse, err := vb.IMouseCapabilityChangedEventgetSupportsMultiTouch(&multiTouchRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	hostCursorRequest := vboxweb.IMouseCapabilityChangedEventgetNeedsHostCursor{This: moid}
+	hostCursorResponse, err := vb.IMouseCapabilityChangedEventgetNeedsHostCursor(&hostCursorRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &MouseCapabilityChangedEvent{
+		BaseEvent:          base,
+		SupportsAbsolute:   absoluteResponse.Returnval,
+		SupportsRelative:   relativeResponse.Returnval,
+		SupportsMultiTouch: multiTouchResponse.Returnval,
+		NeedsHostCursor:    hostCursorResponse.Returnval,
+	}, nil
+}
+
+func decodeGuestSessionStateChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	idRequest := vboxweb.IGuestSessionStateChangedEventgetId{This: moid}
+	idResponse, err := vb.IGuestSessionStateChangedEventgetId(&idRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	statusRequest := vboxweb.IGuestSessionStateChangedEventgetStatus{This: moid}
+	statusResponse, err := vb.IGuestSessionStateChangedEventgetStatus(&statusRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	errorRequest := vboxweb.IGuestSessionStateChangedEventgetError{This: moid}
+	errorResponse, err := vb.IGuestSessionStateChangedEventgetError(&errorRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	text, err := errorInfoText(vb, errorResponse.Returnval)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &GuestSessionStateChangedEvent{BaseEvent: base, ID: idResponse.Returnval, Error: text}
+	if statusResponse.Returnval != nil {
+		ev.Status = *statusResponse.Returnval
+	}
+	return ev, nil
+}
+
+func guestProcessEventPID(vb *VirtualBox, moid string) (uint32, error) {
+	request := vboxweb.IGuestProcessEventgetPid{This: moid}
+
+	response, err := vb.IGuestProcessEventgetPid(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func decodeGuestProcessStateChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	pid, err := guestProcessEventPID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	statusRequest := vboxweb.IGuestProcessStateChangedEventgetStatus{This: moid}
+	statusResponse, err := vb.IGuestProcessStateChangedEventgetStatus(&statusRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	errorRequest := vboxweb.IGuestProcessStateChangedEventgetError{This: moid}
+	errorResponse, err := vb.IGuestProcessStateChangedEventgetError(&errorRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	text, err := errorInfoText(vb, errorResponse.Returnval)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &GuestProcessStateChangedEvent{BaseEvent: base, PID: pid, Error: text}
+	if statusResponse.Returnval != nil {
+		ev.Status = *statusResponse.Returnval
+	}
+	return ev, nil
+}
+
+func decodeGuestProcessOutput(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	pid, err := guestProcessEventPID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	handleRequest := vboxweb.IGuestProcessIOEventgetHandle{This: moid}
+	handleResponse, err := vb.IGuestProcessIOEventgetHandle(&handleRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	dataRequest := vboxweb.IGuestProcessOutputEventgetData{This: moid}
+	dataResponse, err := vb.IGuestProcessOutputEventgetData(&dataRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	data, err := base64.StdEncoding.DecodeString(dataResponse.Returnval)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GuestProcessOutputEvent{BaseEvent: base, PID: pid, Handle: handleResponse.Returnval, Data: data}, nil
+}
+
+func decodeGuestFileStateChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	statusRequest := vboxweb.IGuestFileStateChangedEventgetStatus{This: moid}
+	statusResponse, err := vb.IGuestFileStateChangedEventgetStatus(&statusRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	errorRequest := vboxweb.IGuestFileStateChangedEventgetError{This: moid}
+	errorResponse, err := vb.IGuestFileStateChangedEventgetError(&errorRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	text, err := errorInfoText(vb, errorResponse.Returnval)
+	if err != nil {
+		return nil, err
+	}
+
+	ev := &GuestFileStateChangedEvent{BaseEvent: base, Error: text}
+	if statusResponse.Returnval != nil {
+		ev.Status = *statusResponse.Returnval
+	}
+	return ev, nil
+}
+
+func decodeGuestFileIO(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	offsetRequest := vboxweb.IGuestFileIOEventgetOffset{This: moid}
+	offsetResponse, err := vb.IGuestFileIOEventgetOffset(&offsetRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	processedRequest := vboxweb.IGuestFileIOEventgetProcessed{This: moid}
+	processedResponse, err := vb.IGuestFileIOEventgetProcessed(&processedRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &GuestFileIOEvent{BaseEvent: base, Offset: offsetResponse.Returnval, Processed: processedResponse.Returnval}, nil
+}
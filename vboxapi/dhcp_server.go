@@ -0,0 +1,223 @@
+package vboxapi
+
+import "github.com/blacktop/go-vboxapi/vboxweb"
+
+// DHCPServer is a VirtualBox DHCP server serving an internal, host-only or
+// NAT network.
+type DHCPServer struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+func (ds *DHCPServer) GetEnabled() (bool, error) {
+	request := vboxweb.IDHCPServergetEnabled{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetEnabled(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ds *DHCPServer) SetEnabled(enabled bool) error {
+	request := vboxweb.IDHCPServersetEnabled{This: ds.managedObjectId, Enabled: enabled}
+
+	_, err := ds.virtualbox.IDHCPServersetEnabled(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ds *DHCPServer) GetIPAddress() (string, error) {
+	request := vboxweb.IDHCPServergetIPAddress{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetIPAddress(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ds *DHCPServer) GetNetworkMask() (string, error) {
+	request := vboxweb.IDHCPServergetNetworkMask{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetNetworkMask(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ds *DHCPServer) GetNetworkName() (string, error) {
+	request := vboxweb.IDHCPServergetNetworkName{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetNetworkName(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ds *DHCPServer) GetLowerIP() (string, error) {
+	request := vboxweb.IDHCPServergetLowerIP{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetLowerIP(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ds *DHCPServer) GetUpperIP() (string, error) {
+	request := vboxweb.IDHCPServergetUpperIP{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetUpperIP(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// SetConfiguration sets the server address and mask and the range of
+// addresses it hands out.
+func (ds *DHCPServer) SetConfiguration(ipAddress, networkMask, lowerIP, upperIP string) error {
+	request := vboxweb.IDHCPServersetConfiguration{
+		This:          ds.managedObjectId,
+		IPAddress:     ipAddress,
+		NetworkMask:   networkMask,
+		FromIPAddress: lowerIP,
+		ToIPAddress:   upperIP,
+	}
+
+	_, err := ds.virtualbox.IDHCPServersetConfiguration(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ds *DHCPServer) AddGlobalOption(option vboxweb.DhcpOpt, value string) error {
+	request := vboxweb.IDHCPServeraddGlobalOption{This: ds.managedObjectId, Option: &option, Value: value}
+
+	_, err := ds.virtualbox.IDHCPServeraddGlobalOption(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ds *DHCPServer) GetGlobalOptions() ([]string, error) {
+	request := vboxweb.IDHCPServergetGlobalOptions{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetGlobalOptions(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ds *DHCPServer) GetVMConfigs() ([]string, error) {
+	request := vboxweb.IDHCPServergetVmConfigs{This: ds.managedObjectId}
+
+	response, err := ds.virtualbox.IDHCPServergetVmConfigs(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// AddVMSlotOption sets a DHCP option for the network adapter in slot of the
+// machine named vmName.
+func (ds *DHCPServer) AddVMSlotOption(vmName string, slot int32, option vboxweb.DhcpOpt, value string) error {
+	request := vboxweb.IDHCPServeraddVmSlotOption{
+		This:   ds.managedObjectId,
+		Vmname: vmName,
+		Slot:   slot,
+		Option: &option,
+		Value:  value,
+	}
+
+	_, err := ds.virtualbox.IDHCPServeraddVmSlotOption(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ds *DHCPServer) RemoveVMSlotOptions(vmName string, slot int32) error {
+	request := vboxweb.IDHCPServerremoveVmSlotOptions{This: ds.managedObjectId, Vmname: vmName, Slot: slot}
+
+	_, err := ds.virtualbox.IDHCPServerremoveVmSlotOptions(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ds *DHCPServer) GetVMSlotOptions(vmName string, slot int32) ([]string, error) {
+	request := vboxweb.IDHCPServergetVmSlotOptions{This: ds.managedObjectId, Vmname: vmName, Slot: slot}
+
+	response, err := ds.virtualbox.IDHCPServergetVmSlotOptions(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ds *DHCPServer) GetMacOptions(mac string) ([]string, error) {
+	request := vboxweb.IDHCPServergetMacOptions{This: ds.managedObjectId, Mac: mac}
+
+	response, err := ds.virtualbox.IDHCPServergetMacOptions(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// Start starts the DHCP server process for networkName.
+func (ds *DHCPServer) Start(networkName, trunkName, trunkType string) error {
+	request := vboxweb.IDHCPServerstart{
+		This:        ds.managedObjectId,
+		NetworkName: networkName,
+		TrunkName:   trunkName,
+		TrunkType:   trunkType,
+	}
+
+	_, err := ds.virtualbox.IDHCPServerstart(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ds *DHCPServer) Stop() error {
+	request := vboxweb.IDHCPServerstop{This: ds.managedObjectId}
+
+	_, err := ds.virtualbox.IDHCPServerstop(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ds *DHCPServer) Release() error {
+	return ds.virtualbox.Release(ds.managedObjectId)
+}
@@ -0,0 +1,221 @@
+package vboxapi
+
+import (
+	"context"
+	"encoding/base64"
+	"image"
+	"io"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// Display is the virtual display of a running VM.
+type Display struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// ScreenResolution describes the current mode of a guest screen.
+type ScreenResolution struct {
+	Width        uint32
+	Height       uint32
+	BitsPerPixel uint32
+	XOrigin      int32
+	YOrigin      int32
+	Enabled      bool
+}
+
+// Display returns the display of the console's VM.
+func (c *Console) Display() (*Display, error) {
+	request := vboxweb.IConsolegetDisplay{This: c.managedObjectID}
+
+	response, err := c.virtualbox.IConsolegetDisplay(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Display{virtualbox: c.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// GetScreenResolution queries the current mode of the given guest screen.
+func (d *Display) GetScreenResolution(screenID uint32) (*ScreenResolution, error) {
+	request := vboxweb.IDisplaygetScreenResolution{This: d.managedObjectId, ScreenId: screenID}
+
+	response, err := d.virtualbox.IDisplaygetScreenResolution(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	res := &ScreenResolution{
+		Width:        response.Width,
+		Height:       response.Height,
+		BitsPerPixel: response.BitsPerPixel,
+		XOrigin:      response.XOrigin,
+		YOrigin:      response.YOrigin,
+	}
+	if response.GuestMonitorStatus != nil {
+		res.Enabled = *response.GuestMonitorStatus == vboxweb.GuestMonitorStatusEnabled
+	}
+	return res, nil
+}
+
+// TakeScreenShotToArray captures the given screen scaled to width x height
+// in the requested bitmap format.
+func (d *Display) TakeScreenShotToArray(screenID, width, height uint32, format vboxweb.BitmapFormat) ([]byte, error) {
+	request := vboxweb.IDisplaytakeScreenShotToArray{
+		This:         d.managedObjectId,
+		ScreenId:     screenID,
+		Width:        width,
+		Height:       height,
+		BitmapFormat: &format,
+	}
+
+	response, err := d.virtualbox.IDisplaytakeScreenShotToArray(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return base64.StdEncoding.DecodeString(response.Returnval)
+}
+
+// screenshotPNG captures the given screen at its current resolution as PNG.
+func (d *Display) screenshotPNG(ctx context.Context, screenID uint32) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, err := d.GetScreenResolution(screenID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.TakeScreenShotToArray(screenID, res.Width, res.Height, vboxweb.BitmapFormatPNG)
+}
+
+// Screenshot captures the given screen at its current resolution.
+func (d *Display) Screenshot(ctx context.Context, screenID uint32) (image.Image, error) {
+	data, err := d.screenshotPNG(ctx, screenID)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBitmap(vboxweb.BitmapFormatPNG, 0, 0, data)
+}
+
+// SavePNG captures the given screen at its current resolution and writes
+// it to w as PNG.
+func (d *Display) SavePNG(ctx context.Context, screenID uint32, w io.Writer) error {
+	data, err := d.screenshotPNG(ctx, screenID)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// scaleFactorMultiplier is the fixed-point multiplier VirtualBox uses for
+// scale factors.
+const scaleFactorMultiplier = 10000
+
+// GetScreenResolutions queries the current mode of the first count guest
+// screens, typically the machine's monitor count.
+func (d *Display) GetScreenResolutions(count uint32) ([]*ScreenResolution, error) {
+	resolutions := make([]*ScreenResolution, 0, count)
+	for screenID := uint32(0); screenID < count; screenID++ {
+		res, err := d.GetScreenResolution(screenID)
+		if err != nil {
+			return nil, err
+		}
+		resolutions = append(resolutions, res)
+	}
+	return resolutions, nil
+}
+
+// SetVideoModeHint asks the guest additions to switch the given screen to
+// width x height at bpp bits per pixel. When changeOrigin is set the screen
+// is also moved to x, y in the guest's virtual desktop; disabling a
+// secondary screen turns that monitor off.
+func (d *Display) SetVideoModeHint(screenID uint32, enabled, changeOrigin bool, x, y int32, width, height, bpp uint32) error {
+	request := vboxweb.IDisplaysetVideoModeHint{
+		This:         d.managedObjectId,
+		Display:      screenID,
+		Enabled:      enabled,
+		ChangeOrigin: changeOrigin,
+		OriginX:      x,
+		OriginY:      y,
+		Width:        width,
+		Height:       height,
+		BitsPerPixel: bpp,
+	}
+
+	_, err := d.virtualbox.IDisplaysetVideoModeHint(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// SetResolution asks the guest additions to resize the given screen to
+// width x height, keeping its position and colour depth.
+func (d *Display) SetResolution(screenID, width, height uint32) error {
+	return d.SetVideoModeHint(screenID, true, false, 0, 0, width, height, 0)
+}
+
+// SetSeamlessMode enables or disables seamless guest windows.
+func (d *Display) SetSeamlessMode(enabled bool) error {
+	request := vboxweb.IDisplaysetSeamlessMode{This: d.managedObjectId, Enabled: enabled}
+
+	_, err := d.virtualbox.IDisplaysetSeamlessMode(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// InvalidateAndUpdate redraws all guest screens.
+func (d *Display) InvalidateAndUpdate() error {
+	request := vboxweb.IDisplayinvalidateAndUpdate{This: d.managedObjectId}
+
+	_, err := d.virtualbox.IDisplayinvalidateAndUpdate(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// InvalidateAndUpdateScreen redraws the given guest screen.
+func (d *Display) InvalidateAndUpdateScreen(screenID uint32) error {
+	request := vboxweb.IDisplayinvalidateAndUpdateScreen{This: d.managedObjectId, ScreenId: screenID}
+
+	_, err := d.virtualbox.IDisplayinvalidateAndUpdateScreen(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// NotifyScaleFactorChange tells the VM that the host scales the given screen
+// by scaleW horizontally and scaleH vertically, e.g. 2.0 on a HiDPI display.
+func (d *Display) NotifyScaleFactorChange(screenID uint32, scaleW, scaleH float64) error {
+	request := vboxweb.IDisplaynotifyScaleFactorChange{
+		This:                      d.managedObjectId,
+		ScreenId:                  screenID,
+		U32ScaleFactorWMultiplied: uint32(scaleW * scaleFactorMultiplier),
+		U32ScaleFactorHMultiplied: uint32(scaleH * scaleFactorMultiplier),
+	}
+
+	_, err := d.virtualbox.IDisplaynotifyScaleFactorChange(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (d *Display) Release() error {
+	return d.virtualbox.Release(d.managedObjectId)
+}
@@ -0,0 +1,42 @@
+package vboxapi
+
+import "github.com/blacktop/go-vboxapi/vboxweb"
+
+// Result codes reported by VirtualBox in runtime faults.
+const (
+	vboxEObjectNotFound uint32 = 0x80BB0001
+)
+
+// faultCode extracts the VirtualBox result code from the runtime fault in
+// the detail of a SOAP fault.
+// It returns false if err carries no result code.
+func faultCode(err error) (uint32, bool) {
+	fault, ok := err.(*vboxweb.SOAPFault)
+	if !ok || fault.Detail == nil || fault.Detail.RuntimeFault == nil {
+		return 0, false
+	}
+	return uint32(fault.Detail.RuntimeFault.ResultCode), true
+}
+
+func isObjectNotFound(err error) bool {
+	code, ok := faultCode(err)
+	return ok && code == vboxEObjectNotFound
+}
+
+// errorInfoText reads the message of the IVirtualBoxErrorInfo object moid
+// and releases it. An empty moid yields an empty message.
+func errorInfoText(vb *VirtualBox, moid string) (string, error) {
+	if moid == "" {
+		return "", nil
+	}
+	defer vb.Release(moid)
+
+	request := vboxweb.IVirtualBoxErrorInfogetText{This: moid}
+
+	response, err := vb.IVirtualBoxErrorInfogetText(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
@@ -0,0 +1,56 @@
+package vboxapi
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestFaultCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		fault  string
+		want   uint32
+		wantOK bool
+	}{
+		{
+			name: "runtime fault",
+			fault: `<SOAP-ENV:Fault xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/" xmlns:vbox="http://www.virtualbox.org/">` +
+				`<faultcode>SOAP-ENV:Client</faultcode><faultstring>VirtualBox error: No more entries for directory</faultstring>` +
+				`<detail><vbox:RuntimeFault><resultCode>-2135228415</resultCode><returnval>errinfo</returnval></vbox:RuntimeFault></detail>` +
+				`</SOAP-ENV:Fault>`,
+			want:   vboxEObjectNotFound,
+			wantOK: true,
+		},
+		{
+			name: "invalid object fault",
+			fault: `<SOAP-ENV:Fault xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/" xmlns:vbox="http://www.virtualbox.org/">` +
+				`<faultcode>SOAP-ENV:Client</faultcode><faultstring>Invalid managed object reference (0x80bb0001)</faultstring>` +
+				`<detail><vbox:InvalidObjectFault><badObjectID>deadbeef</badObjectID></vbox:InvalidObjectFault></detail>` +
+				`</SOAP-ENV:Fault>`,
+		},
+		{
+			name: "no detail",
+			fault: `<SOAP-ENV:Fault xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/">` +
+				`<faultcode>SOAP-ENV:Server</faultcode><faultstring>error (0x80bb0001)</faultstring>` +
+				`</SOAP-ENV:Fault>`,
+		},
+	}
+
+	for _, tt := range tests {
+		fault := &vboxweb.SOAPFault{}
+		if err := xml.Unmarshal([]byte(tt.fault), fault); err != nil {
+			t.Fatalf("%s: unmarshal: %v", tt.name, err)
+		}
+		got, ok := faultCode(fault)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: faultCode() = %#x, %v, want %#x, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if _, ok := faultCode(errors.New("plain error")); ok {
+		t.Error("faultCode() reported a code for a non-SOAP error")
+	}
+}
@@ -0,0 +1,331 @@
+package vboxapi
+
+import "github.com/blacktop/go-vboxapi/vboxweb"
+
+// Event is an event delivered by an EventSource. Events without a typed
+// decoder are delivered as *BaseEvent.
+type Event interface {
+	EventType() vboxweb.VBoxEventType
+}
+
+// BaseEvent holds the fields common to all events.
+type BaseEvent struct {
+	Type     vboxweb.VBoxEventType
+	Waitable bool
+}
+
+func (e *BaseEvent) EventType() vboxweb.VBoxEventType {
+	return e.Type
+}
+
+// EventError is delivered when an event could not be fetched or decoded.
+type EventError struct {
+	BaseEvent
+	Err error
+}
+
+func (e *EventError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+type MachineStateChangedEvent struct {
+	BaseEvent
+	MachineID string
+	State     vboxweb.MachineState
+}
+
+type MachineDataChangedEvent struct {
+	BaseEvent
+	MachineID string
+	Temporary bool
+}
+
+type MachineRegisteredEvent struct {
+	BaseEvent
+	MachineID  string
+	Registered bool
+}
+
+type SessionStateChangedEvent struct {
+	BaseEvent
+	MachineID string
+	State     vboxweb.SessionState
+}
+
+type ExtraDataChangedEvent struct {
+	BaseEvent
+	MachineID string
+	Key       string
+	Value     string
+}
+
+type MediumRegisteredEvent struct {
+	BaseEvent
+	MediumID   string
+	MediumType vboxweb.DeviceType
+	Registered bool
+}
+
+// SnapshotEvent is delivered for snapshots being taken, deleted, changed
+// or restored; Type tells which.
+type SnapshotEvent struct {
+	BaseEvent
+	MachineID  string
+	SnapshotID string
+}
+
+type GuestPropertyChangedEvent struct {
+	BaseEvent
+	MachineID string
+	Name      string
+	Value     string
+	Flags     string
+}
+
+// eventDecoder fills a typed event from the event object moid.
+type eventDecoder func(vb *VirtualBox, moid string, base BaseEvent) (Event, error)
+
+var eventDecoders = map[vboxweb.VBoxEventType]eventDecoder{
+	vboxweb.VBoxEventTypeOnMachineStateChanged:      decodeMachineStateChanged,
+	vboxweb.VBoxEventTypeOnMachineDataChanged:       decodeMachineDataChanged,
+	vboxweb.VBoxEventTypeOnMachineRegistered:        decodeMachineRegistered,
+	vboxweb.VBoxEventTypeOnSessionStateChanged:      decodeSessionStateChanged,
+	vboxweb.VBoxEventTypeOnExtraDataChanged:         decodeExtraDataChanged,
+	vboxweb.VBoxEventTypeOnMediumRegistered:         decodeMediumRegistered,
+	vboxweb.VBoxEventTypeOnSnapshotTaken:            decodeSnapshot,
+	vboxweb.VBoxEventTypeOnSnapshotDeleted:          decodeSnapshot,
+	vboxweb.VBoxEventTypeOnSnapshotChanged:          decodeSnapshot,
+	vboxweb.VBoxEventTypeOnSnapshotRestored:         decodeSnapshot,
+	vboxweb.VBoxEventTypeOnGuestPropertyChanged:     decodeGuestPropertyChanged,
+	vboxweb.VBoxEventTypeOnStateChanged:             decodeStateChanged,
+	vboxweb.VBoxEventTypeOnRuntimeError:             decodeRuntimeError,
+	vboxweb.VBoxEventTypeOnUSBDeviceStateChanged:    decodeUSBDeviceStateChanged,
+	vboxweb.VBoxEventTypeOnKeyboardLedsChanged:      decodeKeyboardLedsChanged,
+	vboxweb.VBoxEventTypeOnGuestKeyboard:            decodeGuestKeyboard,
+	vboxweb.VBoxEventTypeOnMousePointerShapeChanged: decodeMousePointerShapeChanged,
+	vboxweb.VBoxEventTypeOnMouseCapabilityChanged:   decodeMouseCapabilityChanged,
+	vboxweb.VBoxEventTypeOnGuestSessionStateChanged: decodeGuestSessionStateChanged,
+	vboxweb.VBoxEventTypeOnGuestProcessStateChanged: decodeGuestProcessStateChanged,
+	vboxweb.VBoxEventTypeOnGuestProcessOutput:       decodeGuestProcessOutput,
+	vboxweb.VBoxEventTypeOnGuestFileStateChanged:    decodeGuestFileStateChanged,
+	vboxweb.VBoxEventTypeOnGuestFileRead:            decodeGuestFileIO,
+	vboxweb.VBoxEventTypeOnGuestFileWrite:           decodeGuestFileIO,
+	vboxweb.VBoxEventTypeOnGuestFileOffsetChanged:   decodeGuestFileIO,
+	vboxweb.VBoxEventTypeOnExtraDataCanChange:       decodeExtraDataCanChange,
+	vboxweb.VBoxEventTypeOnCanShowWindow:            decodeCanShowWindow,
+}
+
+// readBaseEvent reads the type and waitability of the event object moid.
+func readBaseEvent(vb *VirtualBox, moid string) (BaseEvent, error) {
+	typeRequest := vboxweb.IEventgetType{This: moid}
+	typeResponse, err := vb.IEventgetType(&typeRequest)
+	if err != nil {
+		return BaseEvent{}, err // TODO: Wrap the error
+	}
+
+	waitableRequest := vboxweb.IEventgetWaitable{This: moid}
+	waitableResponse, err := vb.IEventgetWaitable(&waitableRequest)
+	if err != nil {
+		return BaseEvent{}, err // TODO: Wrap the error
+	}
+
+	base := BaseEvent{Waitable: waitableResponse.Returnval}
+	if typeResponse.Returnval != nil {
+		base.Type = *typeResponse.Returnval
+	}
+	return base, nil
+}
+
+// decodeEvent decodes the event object moid into the Go struct matching
+// base.Type.
+func decodeEvent(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	decode, ok := eventDecoders[base.Type]
+	if !ok {
+		return &base, nil
+	}
+	return decode(vb, moid, base)
+}
+
+func machineEventMachineID(vb *VirtualBox, moid string) (string, error) {
+	request := vboxweb.IMachineEventgetMachineId{This: moid}
+
+	response, err := vb.IMachineEventgetMachineId(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func decodeMachineStateChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineID, err := machineEventMachineID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	request := vboxweb.IMachineStateChangedEventgetState{This: moid}
+	response, err := vb.IMachineStateChangedEventgetState(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	ev := &MachineStateChangedEvent{BaseEvent: base, MachineID: machineID}
+	if response.Returnval != nil {
+		ev.State = *response.Returnval
+	}
+	return ev, nil
+}
+
+func decodeMachineDataChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineID, err := machineEventMachineID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	request := vboxweb.IMachineDataChangedEventgetTemporary{This: moid}
+	response, err := vb.IMachineDataChangedEventgetTemporary(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &MachineDataChangedEvent{BaseEvent: base, MachineID: machineID, Temporary: response.Returnval}, nil
+}
+
+func decodeMachineRegistered(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineID, err := machineEventMachineID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	request := vboxweb.IMachineRegisteredEventgetRegistered{This: moid}
+	response, err := vb.IMachineRegisteredEventgetRegistered(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &MachineRegisteredEvent{BaseEvent: base, MachineID: machineID, Registered: response.Returnval}, nil
+}
+
+func decodeSessionStateChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineID, err := machineEventMachineID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	request := vboxweb.ISessionStateChangedEventgetState{This: moid}
+	response, err := vb.ISessionStateChangedEventgetState(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	ev := &SessionStateChangedEvent{BaseEvent: base, MachineID: machineID}
+	if response.Returnval != nil {
+		ev.State = *response.Returnval
+	}
+	return ev, nil
+}
+
+func decodeExtraDataChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineRequest := vboxweb.IExtraDataChangedEventgetMachineId{This: moid}
+	machineResponse, err := vb.IExtraDataChangedEventgetMachineId(&machineRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	keyRequest := vboxweb.IExtraDataChangedEventgetKey{This: moid}
+	keyResponse, err := vb.IExtraDataChangedEventgetKey(&keyRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	valueRequest := vboxweb.IExtraDataChangedEventgetValue{This: moid}
+	valueResponse, err := vb.IExtraDataChangedEventgetValue(&valueRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &ExtraDataChangedEvent{
+		BaseEvent: base,
+		MachineID: machineResponse.Returnval,
+		Key:       keyResponse.Returnval,
+		Value:     valueResponse.Returnval,
+	}, nil
+}
+
+func decodeMediumRegistered(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	idRequest := vboxweb.IMediumRegisteredEventgetMediumId{This: moid}
+	idResponse, err := vb.IMediumRegisteredEventgetMediumId(&idRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	typeRequest := vboxweb.IMediumRegisteredEventgetMediumType{This: moid}
+	typeResponse, err := vb.IMediumRegisteredEventgetMediumType(&typeRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	registeredRequest := vboxweb.IMediumRegisteredEventgetRegistered{This: moid}
+	registeredResponse, err := vb.IMediumRegisteredEventgetRegistered(&registeredRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	ev := &MediumRegisteredEvent{BaseEvent: base, MediumID: idResponse.Returnval, Registered: registeredResponse.Returnval}
+	if typeResponse.Returnval != nil {
+		ev.MediumType = *typeResponse.Returnval
+	}
+	return ev, nil
+}
+
+func decodeSnapshot(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineID, err := machineEventMachineID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	request := vboxweb.ISnapshotEventgetSnapshotId{This: moid}
+	response, err := vb.ISnapshotEventgetSnapshotId(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &SnapshotEvent{BaseEvent: base, MachineID: machineID, SnapshotID: response.Returnval}, nil
+}
+
+func decodeGuestPropertyChanged(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineID, err := machineEventMachineID(vb, moid)
+	if err != nil {
+		return nil, err
+	}
+
+	nameRequest := vboxweb.IGuestPropertyChangedEventgetName{This: moid}
+	nameResponse, err := vb.IGuestPropertyChangedEventgetName(&nameRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	valueRequest := vboxweb.IGuestPropertyChangedEventgetValue{This: moid}
+	valueResponse, err := vb.IGuestPropertyChangedEventgetValue(&valueRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	flagsRequest := vboxweb.IGuestPropertyChangedEventgetFlags{This: moid}
+	flagsResponse, err := vb.IGuestPropertyChangedEventgetFlags(&flagsRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &GuestPropertyChangedEvent{
+		BaseEvent: base,
+		MachineID: machineID,
+		Name:      nameResponse.Returnval,
+		Value:     valueResponse.Returnval,
+		Flags:     flagsResponse.Returnval,
+	}, nil
+}
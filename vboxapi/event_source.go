@@ -0,0 +1,205 @@
+package vboxapi
+
+import (
+	"context"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// eventPollTimeout is how long, in milliseconds, each getEvent call waits
+// on the server for an event.
+const eventPollTimeout = 1000
+
+// eventBufferSize is the capacity of the channels returned by Events.
+const eventBufferSize = 16
+
+// EventSource is a VirtualBox event source.
+type EventSource struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// GetEventSource returns the global event source of VirtualBox.
+func (vb *VirtualBox) GetEventSource() (*EventSource, error) {
+	request := vboxweb.IVirtualBoxgetEventSource{This: vb.managedObjectId}
+
+	response, err := vb.IVirtualBoxgetEventSource(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &EventSource{virtualbox: vb, managedObjectId: response.Returnval}, nil
+}
+
+// Events subscribes to the global event source. See EventSource.Events.
+func (vb *VirtualBox) Events(ctx context.Context, types ...vboxweb.VBoxEventType) (<-chan Event, error) {
+	es, err := vb.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.events(ctx, true, types)
+}
+
+// Events registers a passive listener for the given event types, or for
+// all events if none are given, and delivers them on the returned channel.
+// Waitable events are marked processed before they are delivered. When ctx
+// is done the listener is unregistered and the channel closed. A failure to
+// fetch or decode an event is delivered as an *EventError; the channel is
+// closed after a fetch failure.
+func (es *EventSource) Events(ctx context.Context, types ...vboxweb.VBoxEventType) (<-chan Event, error) {
+	return es.events(ctx, false, types)
+}
+
+// events implements Events. If owned is set the event source is released
+// together with the listener.
+func (es *EventSource) events(ctx context.Context, owned bool, types []vboxweb.VBoxEventType) (<-chan Event, error) {
+	l, err := es.listen(types)
+	if err != nil {
+		if owned {
+			es.Release()
+		}
+		return nil, err
+	}
+
+	ch := make(chan Event, eventBufferSize)
+	go func() {
+		defer close(ch)
+		defer func() {
+			l.close()
+			if owned {
+				es.Release()
+			}
+		}()
+
+		for ctx.Err() == nil {
+			moid, err := l.next()
+			if err != nil {
+				select {
+				case ch <- &EventError{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			if moid == "" {
+				continue
+			}
+
+			select {
+			case ch <- l.handle(moid, nil):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (es *EventSource) Release() error {
+	return es.virtualbox.Release(es.managedObjectId)
+}
+
+// eventListener is a passive listener registered on an EventSource.
+type eventListener struct {
+	source          *EventSource
+	managedObjectId string
+}
+
+// listen creates a passive listener and registers it for the given event
+// types, or for all events if none are given.
+func (es *EventSource) listen(types []vboxweb.VBoxEventType) (*eventListener, error) {
+	request := vboxweb.IEventSourcecreateListener{This: es.managedObjectId}
+
+	response, err := es.virtualbox.IEventSourcecreateListener(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	l := &eventListener{source: es, managedObjectId: response.Returnval}
+
+	if len(types) == 0 {
+		types = []vboxweb.VBoxEventType{vboxweb.VBoxEventTypeAny}
+	}
+	interesting := make([]*vboxweb.VBoxEventType, len(types))
+	for i := range types {
+		interesting[i] = &types[i]
+	}
+	registerRequest := vboxweb.IEventSourceregisterListener{
+		This:        es.managedObjectId,
+		Listener:    l.managedObjectId,
+		Interesting: interesting,
+		Active:      false,
+	}
+
+	_, err = es.virtualbox.IEventSourceregisterListener(&registerRequest)
+	if err != nil {
+		es.virtualbox.Release(l.managedObjectId)
+		return nil, err // TODO: Wrap the error
+	}
+
+	return l, nil
+}
+
+// next waits up to eventPollTimeout for an event.
+// It returns the event object, empty if none arrived.
+func (l *eventListener) next() (string, error) {
+	request := vboxweb.IEventSourcegetEvent{
+		This:     l.source.managedObjectId,
+		Listener: l.managedObjectId,
+		Timeout:  eventPollTimeout,
+	}
+
+	response, err := l.source.virtualbox.IEventSourcegetEvent(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// processed marks the event object moid as processed by this listener.
+func (l *eventListener) processed(moid string) error {
+	request := vboxweb.IEventSourceeventProcessed{
+		This:     l.source.managedObjectId,
+		Listener: l.managedObjectId,
+		Event:    moid,
+	}
+
+	_, err := l.source.virtualbox.IEventSourceeventProcessed(&request)
+	return err // TODO: Wrap the error
+}
+
+// handle decodes the event object moid, passes it to fn if fn is not nil,
+// marks it processed if it is waitable and releases it.
+// It returns the decoded event, or an *EventError if decoding failed.
+func (l *eventListener) handle(moid string, fn func(moid string, ev Event)) Event {
+	vb := l.source.virtualbox
+	defer vb.Release(moid)
+
+	base, err := readBaseEvent(vb, moid)
+	if err != nil {
+		return &EventError{Err: err}
+	}
+	if base.Waitable {
+		defer l.processed(moid)
+	}
+
+	ev, err := decodeEvent(vb, moid, base)
+	if err != nil {
+		ev = &EventError{BaseEvent: base, Err: err}
+	}
+	if fn != nil {
+		fn(moid, ev)
+	}
+	return ev
+}
+
+// close unregisters and releases the listener.
+func (l *eventListener) close() error {
+	request := vboxweb.IEventSourceunregisterListener{
+		This:     l.source.managedObjectId,
+		Listener: l.managedObjectId,
+	}
+
+	_, err := l.source.virtualbox.IEventSourceunregisterListener(&request)
+	l.source.virtualbox.Release(l.managedObjectId)
+	return err // TODO: Wrap the error
+}
@@ -0,0 +1,99 @@
+package vboxapi
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// fakeCall is a SOAP request received by a fakeVBox.
+type fakeCall struct {
+	// Method is the request element name, e.g. "IMachine_getState".
+	Method string
+	// Request is the request element, including its start and end tags.
+	Request []byte
+}
+
+// fakeVBox is a minimal vboxwebsrv answering each method with a canned
+// response body. Methods without a response get an empty one.
+type fakeVBox struct {
+	*VirtualBox
+
+	mu        sync.Mutex
+	responses map[string][]string
+	calls     []fakeCall
+}
+
+// newFakeVBox starts a fake vboxwebsrv and returns a client connected to it.
+func newFakeVBox(t *testing.T) *fakeVBox {
+	f := &fakeVBox{responses: make(map[string][]string)}
+	srv := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(srv.Close)
+	f.VirtualBox = New("", "", srv.URL, false, "")
+	return f
+}
+
+// respond queues the inner XML of the responses to method. The last one is
+// repeated for further calls.
+func (f *fakeVBox) respond(method string, bodies ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses[method] = append(f.responses[method], bodies...)
+}
+
+// requests returns the requests received for method.
+func (f *fakeVBox) requests(method string) [][]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var reqs [][]byte
+	for _, c := range f.calls {
+		if c.Method == method {
+			reqs = append(reqs, c.Request)
+		}
+	}
+	return reqs
+}
+
+func (f *fakeVBox) serve(w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var envelope struct {
+		Body struct {
+			Inner []byte `xml:",innerxml"`
+		} `xml:"Body"`
+	}
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(envelope.Body.Inner, &request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	method := request.XMLName.Local
+
+	f.mu.Lock()
+	f.calls = append(f.calls, fakeCall{Method: method, Request: envelope.Body.Inner})
+	var body string
+	if queued := f.responses[method]; len(queued) > 0 {
+		body = queued[0]
+		if len(queued) > 1 {
+			f.responses[method] = queued[1:]
+		}
+	}
+	f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+	fmt.Fprintf(w, `<SOAP-ENV:Envelope xmlns:SOAP-ENV="http://schemas.xmlsoap.org/soap/envelope/" xmlns:vbox="http://www.virtualbox.org/">`+
+		`<SOAP-ENV:Body><vbox:%sResponse>%s</vbox:%sResponse></SOAP-ENV:Body></SOAP-ENV:Envelope>`, method, body, method)
+}
@@ -0,0 +1,113 @@
+package vboxapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// guestSessionStartTimeout is how long CreateSession waits, in milliseconds,
+// for the guest additions to start a new session.
+const guestSessionStartTimeout = 30000
+
+// Guest is the guest operating system of a running VM, reached through the
+// guest additions.
+type Guest struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// Guest returns the guest of the console's VM.
+func (c *Console) Guest() (*Guest, error) {
+	request := vboxweb.IConsolegetGuest{This: c.managedObjectID}
+
+	response, err := c.virtualbox.IConsolegetGuest(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Guest{virtualbox: c.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// CreateSession opens a guest session as user and waits for the guest
+// additions to start it.
+func (g *Guest) CreateSession(user, password, domain string) (*GuestSession, error) {
+	request := vboxweb.IGuestcreateSession{
+		This:        g.managedObjectId,
+		User:        user,
+		Password:    password,
+		Domain:      domain,
+		SessionName: "go-vboxapi",
+	}
+
+	response, err := g.virtualbox.IGuestcreateSession(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	gs := &GuestSession{virtualbox: g.virtualbox, managedObjectId: response.Returnval}
+
+	result, err := gs.waitFor(guestSessionStartTimeout, vboxweb.GuestSessionWaitForFlagStart)
+	if err != nil {
+		gs.Close()
+		return nil, err
+	}
+	if result != vboxweb.GuestSessionWaitResultStart {
+		gs.Close()
+		return nil, fmt.Errorf("guest session did not start: %s", result)
+	}
+
+	return gs, nil
+}
+
+// GetAdditionsRunLevel returns how far the Guest Additions have started in
+// the guest.
+func (g *Guest) GetAdditionsRunLevel() (*vboxweb.AdditionsRunLevelType, error) {
+	request := vboxweb.IGuestgetAdditionsRunLevel{This: g.managedObjectId}
+
+	response, err := g.virtualbox.IGuestgetAdditionsRunLevel(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (g *Guest) GetAdditionsVersion() (string, error) {
+	request := vboxweb.IGuestgetAdditionsVersion{This: g.managedObjectId}
+
+	response, err := g.virtualbox.IGuestgetAdditionsVersion(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GetEventSource returns the event source of the guest, which delivers
+// guest session registration and state events.
+func (g *Guest) GetEventSource() (*EventSource, error) {
+	request := vboxweb.IGuestgetEventSource{This: g.managedObjectId}
+
+	response, err := g.virtualbox.IGuestgetEventSource(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &EventSource{virtualbox: g.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// Events subscribes to the event source of the guest. See
+// EventSource.Events.
+func (g *Guest) Events(ctx context.Context, types ...vboxweb.VBoxEventType) (<-chan Event, error) {
+	es, err := g.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.events(ctx, true, types)
+}
+
+func (g *Guest) Release() error {
+	return g.virtualbox.Release(g.managedObjectId)
+}
@@ -0,0 +1,110 @@
+package vboxapi
+
+import (
+	"context"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// CopyToGuest copies the host file hostPath to guestPath in the guest and
+// waits for the copy to finish. The copy is cancelled when ctx is done.
+func (gs *GuestSession) CopyToGuest(ctx context.Context, hostPath, guestPath string, flags []vboxweb.FileCopyFlag) error {
+	request := vboxweb.IGuestSessionfileCopyToGuest{
+		This:        gs.managedObjectId,
+		Source:      hostPath,
+		Destination: guestPath,
+		Flags:       fileCopyFlags(flags),
+	}
+
+	response, err := gs.virtualbox.IGuestSessionfileCopyToGuest(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return gs.waitProgress(ctx, response.Returnval)
+}
+
+// CopyFromGuest copies the guest file guestPath to hostPath on the host and
+// waits for the copy to finish. The copy is cancelled when ctx is done.
+func (gs *GuestSession) CopyFromGuest(ctx context.Context, guestPath, hostPath string, flags []vboxweb.FileCopyFlag) error {
+	request := vboxweb.IGuestSessionfileCopyFromGuest{
+		This:        gs.managedObjectId,
+		Source:      guestPath,
+		Destination: hostPath,
+		Flags:       fileCopyFlags(flags),
+	}
+
+	response, err := gs.virtualbox.IGuestSessionfileCopyFromGuest(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return gs.waitProgress(ctx, response.Returnval)
+}
+
+// CopyDirectoryToGuest recursively copies the host directory hostPath to
+// guestPath in the guest and waits for the copy to finish. The copy is
+// cancelled when ctx is done.
+func (gs *GuestSession) CopyDirectoryToGuest(ctx context.Context, hostPath, guestPath string, flags []vboxweb.DirectoryCopyFlags) error {
+	request := vboxweb.IGuestSessiondirectoryCopyToGuest{
+		This:        gs.managedObjectId,
+		Source:      hostPath,
+		Destination: guestPath,
+		Flags:       directoryCopyFlags(flags),
+	}
+
+	response, err := gs.virtualbox.IGuestSessiondirectoryCopyToGuest(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return gs.waitProgress(ctx, response.Returnval)
+}
+
+// CopyDirectoryFromGuest recursively copies the guest directory guestPath to
+// hostPath on the host and waits for the copy to finish. The copy is
+// cancelled when ctx is done.
+func (gs *GuestSession) CopyDirectoryFromGuest(ctx context.Context, guestPath, hostPath string, flags []vboxweb.DirectoryCopyFlags) error {
+	request := vboxweb.IGuestSessiondirectoryCopyFromGuest{
+		This:        gs.managedObjectId,
+		Source:      guestPath,
+		Destination: hostPath,
+		Flags:       directoryCopyFlags(flags),
+	}
+
+	response, err := gs.virtualbox.IGuestSessiondirectoryCopyFromGuest(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return gs.waitProgress(ctx, response.Returnval)
+}
+
+func (gs *GuestSession) waitProgress(ctx context.Context, moid string) error {
+	progress := &Progress{virtualbox: gs.virtualbox, managedObjectId: moid}
+	defer progress.Release()
+
+	return progress.Wait(ctx, nil)
+}
+
+func fileCopyFlags(flags []vboxweb.FileCopyFlag) []*vboxweb.FileCopyFlag {
+	if len(flags) == 0 {
+		flags = []vboxweb.FileCopyFlag{vboxweb.FileCopyFlagNone}
+	}
+	ret := make([]*vboxweb.FileCopyFlag, len(flags))
+	for i := range flags {
+		ret[i] = &flags[i]
+	}
+	return ret
+}
+
+func directoryCopyFlags(flags []vboxweb.DirectoryCopyFlags) []*vboxweb.DirectoryCopyFlags {
+	if len(flags) == 0 {
+		flags = []vboxweb.DirectoryCopyFlags{vboxweb.DirectoryCopyFlagsNone}
+	}
+	ret := make([]*vboxweb.DirectoryCopyFlags, len(flags))
+	for i := range flags {
+		ret[i] = &flags[i]
+	}
+	return ret
+}
@@ -0,0 +1,224 @@
+package vboxapi
+
+import (
+	"path"
+	"strings"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// GuestEnv is the environment of a guest session. The base environment is
+// the one the session was started with; scheduled changes are applied on
+// top of it for every process started afterwards.
+type GuestEnv struct {
+	session *GuestSession
+}
+
+// Env returns the environment of the session.
+func (gs *GuestSession) Env() *GuestEnv {
+	return &GuestEnv{session: gs}
+}
+
+// Base returns the base environment as "NAME=VALUE" strings.
+func (e *GuestEnv) Base() ([]string, error) {
+	request := vboxweb.IGuestSessiongetEnvironmentBase{This: e.session.managedObjectId}
+
+	response, err := e.session.virtualbox.IGuestSessiongetEnvironmentBase(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// Lookup returns the value of the base environment variable name and
+// whether it is set.
+func (e *GuestEnv) Lookup(name string) (string, bool, error) {
+	existsRequest := vboxweb.IGuestSessionenvironmentDoesBaseVariableExist{This: e.session.managedObjectId, Name: name}
+
+	existsResponse, err := e.session.virtualbox.IGuestSessionenvironmentDoesBaseVariableExist(&existsRequest)
+	if err != nil {
+		return "", false, err // TODO: Wrap the error
+	}
+	if !existsResponse.Returnval {
+		return "", false, nil
+	}
+
+	request := vboxweb.IGuestSessionenvironmentGetBaseVariable{This: e.session.managedObjectId, Name: name}
+
+	response, err := e.session.virtualbox.IGuestSessionenvironmentGetBaseVariable(&request)
+	if err != nil {
+		return "", false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, true, nil
+}
+
+// Set schedules name to be set to value for subsequently started processes.
+func (e *GuestEnv) Set(name, value string) error {
+	request := vboxweb.IGuestSessionenvironmentScheduleSet{This: e.session.managedObjectId, Name: name, Value: value}
+
+	_, err := e.session.virtualbox.IGuestSessionenvironmentScheduleSet(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Unset schedules name to be removed for subsequently started processes.
+func (e *GuestEnv) Unset(name string) error {
+	request := vboxweb.IGuestSessionenvironmentScheduleUnset{This: e.session.managedObjectId, Name: name}
+
+	_, err := e.session.virtualbox.IGuestSessionenvironmentScheduleUnset(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Changes returns the scheduled changes as "NAME=VALUE" strings, with unset
+// variables given as "NAME".
+func (e *GuestEnv) Changes() ([]string, error) {
+	request := vboxweb.IGuestSessiongetEnvironmentChanges{This: e.session.managedObjectId}
+
+	response, err := e.session.virtualbox.IGuestSessiongetEnvironmentChanges(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// SetChanges replaces all scheduled changes.
+func (e *GuestEnv) SetChanges(changes []string) error {
+	request := vboxweb.IGuestSessionsetEnvironmentChanges{This: e.session.managedObjectId, EnvironmentChanges: changes}
+
+	_, err := e.session.virtualbox.IGuestSessionsetEnvironmentChanges(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (gs *GuestSession) GetPathStyle() (vboxweb.PathStyle, error) {
+	request := vboxweb.IGuestSessiongetPathStyle{This: gs.managedObjectId}
+
+	response, err := gs.virtualbox.IGuestSessiongetPathStyle(&request)
+	if err != nil {
+		return vboxweb.PathStyleUnknown, err // TODO: Wrap the error
+	}
+
+	if response.Returnval == nil {
+		return vboxweb.PathStyleUnknown, nil
+	}
+	return *response.Returnval, nil
+}
+
+func (gs *GuestSession) GetCurrentDirectory() (string, error) {
+	request := vboxweb.IGuestSessiongetCurrentDirectory{This: gs.managedObjectId}
+
+	response, err := gs.virtualbox.IGuestSessiongetCurrentDirectory(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GetTimeout returns the default timeout, in milliseconds, of operations in
+// the session.
+func (gs *GuestSession) GetTimeout() (uint32, error) {
+	request := vboxweb.IGuestSessiongetTimeout{This: gs.managedObjectId}
+
+	response, err := gs.virtualbox.IGuestSessiongetTimeout(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (gs *GuestSession) SetTimeout(timeout uint32) error {
+	request := vboxweb.IGuestSessionsetTimeout{This: gs.managedObjectId, Timeout: timeout}
+
+	_, err := gs.virtualbox.IGuestSessionsetTimeout(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// JoinPath joins path elements with the separator of the guest's path
+// style, cleaning the result.
+func (gs *GuestSession) JoinPath(elem ...string) (string, error) {
+	style, err := gs.GetPathStyle()
+	if err != nil {
+		return "", err
+	}
+
+	return joinGuestPath(style, elem...), nil
+}
+
+// joinGuestPath joins elem according to style. DOS paths are joined as
+// slash-separated paths and converted back, keeping drive letters, the
+// separator of a drive root and the \\server\share prefix of UNC paths.
+func joinGuestPath(style vboxweb.PathStyle, elem ...string) string {
+	if style != vboxweb.PathStyleDOS {
+		return path.Join(elem...)
+	}
+
+	slashed := make([]string, 0, len(elem))
+	for _, e := range elem {
+		if e != "" {
+			slashed = append(slashed, strings.Replace(e, `\`, "/", -1))
+		}
+	}
+	if len(slashed) == 0 {
+		return ""
+	}
+
+	volume, rest := splitDOSVolume(slashed[0])
+	slashed[0] = rest
+	if strings.HasPrefix(volume, "//") {
+		// The share is the root of a UNC path.
+		slashed[0] = "/" + strings.TrimPrefix(rest, "/")
+	}
+
+	joined := path.Join(slashed...)
+	switch {
+	case strings.HasPrefix(volume, "//") && joined == "/":
+		joined = ""
+	case volume != "" && joined == ".":
+		joined = ""
+	}
+	return strings.Replace(volume+joined, "/", `\`, -1)
+}
+
+// splitDOSVolume splits the slash-separated DOS path p into its drive letter
+// or //server/share prefix and the rest of the path.
+func splitDOSVolume(p string) (volume, rest string) {
+	if len(p) >= 2 && p[1] == ':' && ('a' <= p[0] && p[0] <= 'z' || 'A' <= p[0] && p[0] <= 'Z') {
+		return p[:2], p[2:]
+	}
+	if !strings.HasPrefix(p, "//") {
+		return "", p
+	}
+
+	// Skip the server and share names.
+	end := 2
+	for n := 0; n < 2; n++ {
+		i := strings.IndexByte(p[end:], '/')
+		if i < 0 {
+			return p, ""
+		}
+		end += i
+		if n == 0 {
+			end++
+		}
+	}
+	return p[:end], p[end:]
+}
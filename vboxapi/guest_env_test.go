@@ -0,0 +1,37 @@
+package vboxapi
+
+import (
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestJoinGuestPath(t *testing.T) {
+	tests := []struct {
+		style vboxweb.PathStyle
+		elem  []string
+		want  string
+	}{
+		{vboxweb.PathStyleUNIX, []string{"/home/user", "dir", "file.txt"}, "/home/user/dir/file.txt"},
+		{vboxweb.PathStyleUNIX, []string{"/tmp/", "../etc", "hosts"}, "/etc/hosts"},
+		{vboxweb.PathStyleDOS, []string{`C:\Users`, "user", `Desktop\file.txt`}, `C:\Users\user\Desktop\file.txt`},
+		{vboxweb.PathStyleDOS, []string{`C:\Windows\`, `..\Temp`}, `C:\Temp`},
+		{vboxweb.PathStyleDOS, []string{`C:\`}, `C:\`},
+		{vboxweb.PathStyleDOS, []string{`C:\`, "file.txt"}, `C:\file.txt`},
+		{vboxweb.PathStyleDOS, []string{`C:\Temp`, ".."}, `C:\`},
+		{vboxweb.PathStyleDOS, []string{`C:\`, `..\..`}, `C:\`},
+		{vboxweb.PathStyleDOS, []string{"C:", "dir"}, `C:dir`},
+		{vboxweb.PathStyleDOS, []string{`\\server\share`}, `\\server\share`},
+		{vboxweb.PathStyleDOS, []string{`\\server\share\dir`, `..\..`}, `\\server\share`},
+		{vboxweb.PathStyleDOS, []string{`\\server\share`, "dir", "file.txt"}, `\\server\share\dir\file.txt`},
+		{vboxweb.PathStyleDOS, []string{"", `\\server\share\`, `dir\`}, `\\server\share\dir`},
+		{vboxweb.PathStyleDOS, []string{`dir`, `\\sub`}, `dir\sub`},
+		{vboxweb.PathStyleDOS, []string{"", ""}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := joinGuestPath(tt.style, tt.elem...); got != tt.want {
+			t.Errorf("joinGuestPath(%s, %q) = %q, want %q", tt.style, tt.elem, got, tt.want)
+		}
+	}
+}
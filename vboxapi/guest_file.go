@@ -0,0 +1,227 @@
+package vboxapi
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// guestFileTimeout is how long, in milliseconds, a single guest file
+// operation may take.
+const guestFileTimeout = 30000
+
+// guestFileChunkSize is the maximum number of bytes transferred by a single
+// guest file read or write request.
+const guestFileChunkSize = 64 * 1024
+
+// GuestFile is a file opened in the guest by GuestSession.OpenFile.
+type GuestFile struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+var (
+	_ io.ReadWriteSeeker = (*GuestFile)(nil)
+	_ io.ReaderAt        = (*GuestFile)(nil)
+	_ io.WriterAt        = (*GuestFile)(nil)
+	_ io.Closer          = (*GuestFile)(nil)
+)
+
+// OpenFile opens the guest file path. mode holds the UNIX permission bits
+// used when the file is created.
+func (gs *GuestSession) OpenFile(path string, access vboxweb.FileAccessMode, disposition vboxweb.FileOpenAction, mode uint32) (*GuestFile, error) {
+	sharing := vboxweb.FileSharingModeAll
+	flag := vboxweb.FileOpenExFlagsNone
+	request := vboxweb.IGuestSessionfileOpenEx{
+		This:         gs.managedObjectId,
+		Path:         path,
+		AccessMode:   &access,
+		OpenAction:   &disposition,
+		SharingMode:  &sharing,
+		CreationMode: mode,
+		Flags:        []*vboxweb.FileOpenExFlags{&flag},
+	}
+
+	response, err := gs.virtualbox.IGuestSessionfileOpenEx(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &GuestFile{virtualbox: gs.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// Read reads up to len(p) bytes from the current offset of the file.
+func (f *GuestFile) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if len(p) > guestFileChunkSize {
+		p = p[:guestFileChunkSize]
+	}
+
+	request := vboxweb.IFileread{This: f.managedObjectId, ToRead: uint32(len(p)), TimeoutMS: guestFileTimeout}
+
+	response, err := f.virtualbox.IFileread(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	data, err := base64.StdEncoding.DecodeString(response.Returnval)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) == 0 {
+		return 0, io.EOF
+	}
+
+	return copy(p, data), nil
+}
+
+// ReadAt reads len(p) bytes starting at offset off of the file. It does not
+// change the current offset.
+func (f *GuestFile) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	for n < len(p) {
+		chunk := p[n:]
+		if len(chunk) > guestFileChunkSize {
+			chunk = chunk[:guestFileChunkSize]
+		}
+
+		request := vboxweb.IFilereadAt{
+			This:      f.managedObjectId,
+			Offset:    off + int64(n),
+			ToRead:    uint32(len(chunk)),
+			TimeoutMS: guestFileTimeout,
+		}
+
+		response, err := f.virtualbox.IFilereadAt(&request)
+		if err != nil {
+			return n, err // TODO: Wrap the error
+		}
+
+		data, err := base64.StdEncoding.DecodeString(response.Returnval)
+		if err != nil {
+			return n, err
+		}
+		if len(data) == 0 {
+			return n, io.EOF
+		}
+		n += copy(chunk, data)
+	}
+
+	return n, nil
+}
+
+// Write writes p at the current offset of the file.
+func (f *GuestFile) Write(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		chunk := p[n:]
+		if len(chunk) > guestFileChunkSize {
+			chunk = chunk[:guestFileChunkSize]
+		}
+
+		request := vboxweb.IFilewrite{
+			This:      f.managedObjectId,
+			Data:      base64.StdEncoding.EncodeToString(chunk),
+			TimeoutMS: guestFileTimeout,
+		}
+
+		response, err := f.virtualbox.IFilewrite(&request)
+		if err != nil {
+			return n, err // TODO: Wrap the error
+		}
+		if response.Returnval == 0 {
+			return n, io.ErrShortWrite
+		}
+		n += int(response.Returnval)
+	}
+
+	return n, nil
+}
+
+// WriteAt writes p starting at offset off of the file.
+func (f *GuestFile) WriteAt(p []byte, off int64) (int, error) {
+	n := 0
+	for n < len(p) {
+		chunk := p[n:]
+		if len(chunk) > guestFileChunkSize {
+			chunk = chunk[:guestFileChunkSize]
+		}
+
+		request := vboxweb.IFilewriteAt{
+			This:      f.managedObjectId,
+			Offset:    off + int64(n),
+			Data:      base64.StdEncoding.EncodeToString(chunk),
+			TimeoutMS: guestFileTimeout,
+		}
+
+		response, err := f.virtualbox.IFilewriteAt(&request)
+		if err != nil {
+			return n, err // TODO: Wrap the error
+		}
+		if response.Returnval == 0 {
+			return n, io.ErrShortWrite
+		}
+		n += int(response.Returnval)
+	}
+
+	return n, nil
+}
+
+// Seek sets the offset for the next Read or Write on the file.
+func (f *GuestFile) Seek(offset int64, whence int) (int64, error) {
+	var origin vboxweb.FileSeekOrigin
+	switch whence {
+	case io.SeekStart:
+		origin = vboxweb.FileSeekOriginBegin
+	case io.SeekCurrent:
+		origin = vboxweb.FileSeekOriginCurrent
+	case io.SeekEnd:
+		origin = vboxweb.FileSeekOriginEnd
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	request := vboxweb.IFileseek{This: f.managedObjectId, Offset: offset, Whence: &origin}
+
+	response, err := f.virtualbox.IFileseek(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// Size returns the current size of the file.
+func (f *GuestFile) Size() (int64, error) {
+	request := vboxweb.IFilequerySize{This: f.managedObjectId}
+
+	response, err := f.virtualbox.IFilequerySize(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// Close closes the file in the guest and releases the file object, even if
+// closing fails.
+func (f *GuestFile) Close() error {
+	defer f.Release()
+
+	request := vboxweb.IFileclose{This: f.managedObjectId}
+
+	_, err := f.virtualbox.IFileclose(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (f *GuestFile) Release() error {
+	return f.virtualbox.Release(f.managedObjectId)
+}
@@ -0,0 +1,207 @@
+package vboxapi
+
+import (
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// GuestFileInfo describes a guest file system object. It implements
+// fs.FileInfo and fs.DirEntry.
+type GuestFileInfo struct {
+	FileName         string
+	ObjectSize       int64
+	ObjectType       vboxweb.FsObjType
+	FileAttributes   string
+	ModificationTime time.Time
+	UID              uint32
+	GID              uint32
+}
+
+var (
+	_ fs.FileInfo = (*GuestFileInfo)(nil)
+	_ fs.DirEntry = (*GuestFileInfo)(nil)
+)
+
+func (fi *GuestFileInfo) Name() string       { return fi.FileName }
+func (fi *GuestFileInfo) Size() int64        { return fi.ObjectSize }
+func (fi *GuestFileInfo) ModTime() time.Time { return fi.ModificationTime }
+func (fi *GuestFileInfo) IsDir() bool        { return fi.ObjectType == vboxweb.FsObjTypeDirectory }
+func (fi *GuestFileInfo) Sys() interface{}   { return nil }
+
+func (fi *GuestFileInfo) Type() fs.FileMode { return fi.Mode().Type() }
+
+// Mode returns the file mode bits. The type bits come from the object type
+// and the permission bits from the "rwxrwxrwx" part of the file attributes.
+func (fi *GuestFileInfo) Mode() fs.FileMode {
+	var mode fs.FileMode
+	switch fi.ObjectType {
+	case vboxweb.FsObjTypeDirectory:
+		mode |= fs.ModeDir
+	case vboxweb.FsObjTypeSymlink:
+		mode |= fs.ModeSymlink
+	case vboxweb.FsObjTypeFifo:
+		mode |= fs.ModeNamedPipe
+	case vboxweb.FsObjTypeSocket:
+		mode |= fs.ModeSocket
+	case vboxweb.FsObjTypeDevChar:
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case vboxweb.FsObjTypeDevBlock:
+		mode |= fs.ModeDevice
+	case vboxweb.FsObjTypeFile:
+	default:
+		mode |= fs.ModeIrregular
+	}
+
+	attrs := fi.FileAttributes
+	if len(attrs) >= 10 {
+		const rwx = "rwxrwxrwx"
+		for i := 0; i < 9; i++ {
+			if attrs[i+1] == rwx[i] {
+				mode |= 1 << uint(8-i)
+			}
+		}
+	}
+
+	return mode
+}
+
+// Info returns the file info itself, for fs.DirEntry.
+func (fi *GuestFileInfo) Info() (fs.FileInfo, error) { return fi, nil }
+
+// fsObjInfo is a VirtualBox file system object information object.
+type fsObjInfo struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+func (oi *fsObjInfo) getName() (string, error) {
+	request := vboxweb.IFsObjInfogetName{This: oi.managedObjectId}
+
+	response, err := oi.virtualbox.IFsObjInfogetName(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (oi *fsObjInfo) getObjectSize() (int64, error) {
+	request := vboxweb.IFsObjInfogetObjectSize{This: oi.managedObjectId}
+
+	response, err := oi.virtualbox.IFsObjInfogetObjectSize(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (oi *fsObjInfo) getType() (*vboxweb.FsObjType, error) {
+	request := vboxweb.IFsObjInfogetType{This: oi.managedObjectId}
+
+	response, err := oi.virtualbox.IFsObjInfogetType(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (oi *fsObjInfo) getFileAttributes() (string, error) {
+	request := vboxweb.IFsObjInfogetFileAttributes{This: oi.managedObjectId}
+
+	response, err := oi.virtualbox.IFsObjInfogetFileAttributes(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (oi *fsObjInfo) getModificationTime() (int64, error) {
+	request := vboxweb.IFsObjInfogetModificationTime{This: oi.managedObjectId}
+
+	response, err := oi.virtualbox.IFsObjInfogetModificationTime(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (oi *fsObjInfo) getUID() (uint32, error) {
+	request := vboxweb.IFsObjInfogetUID{This: oi.managedObjectId}
+
+	response, err := oi.virtualbox.IFsObjInfogetUID(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (oi *fsObjInfo) getGID() (uint32, error) {
+	request := vboxweb.IFsObjInfogetGID{This: oi.managedObjectId}
+
+	response, err := oi.virtualbox.IFsObjInfogetGID(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (oi *fsObjInfo) release() error {
+	return oi.virtualbox.Release(oi.managedObjectId)
+}
+
+// get reads the object information into a GuestFileInfo.
+func (oi *fsObjInfo) get() (*GuestFileInfo, error) {
+	var err error
+	fi := &GuestFileInfo{}
+
+	name, err := oi.getName()
+	if err != nil {
+		return nil, err
+	}
+	fi.FileName = path.Base(name)
+
+	fi.ObjectSize, err = oi.getObjectSize()
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := oi.getType()
+	if err != nil {
+		return nil, err
+	}
+	if t != nil {
+		fi.ObjectType = *t
+	}
+
+	fi.FileAttributes, err = oi.getFileAttributes()
+	if err != nil {
+		return nil, err
+	}
+
+	mtime, err := oi.getModificationTime()
+	if err != nil {
+		return nil, err
+	}
+	fi.ModificationTime = time.Unix(0, mtime)
+
+	fi.UID, err = oi.getUID()
+	if err != nil {
+		return nil, err
+	}
+
+	fi.GID, err = oi.getGID()
+	if err != nil {
+		return nil, err
+	}
+
+	return fi, nil
+}
@@ -0,0 +1,452 @@
+package vboxapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sort"
+	"sync"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// CreateDirectory creates the guest directory path with the UNIX permission
+// bits mode. If parents is true, missing parent directories are created too.
+func (gs *GuestSession) CreateDirectory(path string, mode uint32, parents bool) error {
+	flag := vboxweb.DirectoryCreateFlagNone
+	if parents {
+		flag = vboxweb.DirectoryCreateFlagParents
+	}
+	request := vboxweb.IGuestSessiondirectoryCreate{
+		This:  gs.managedObjectId,
+		Path:  path,
+		Mode:  mode,
+		Flags: []*vboxweb.DirectoryCreateFlag{&flag},
+	}
+
+	_, err := gs.virtualbox.IGuestSessiondirectoryCreate(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// CreateTempDirectory creates a uniquely named directory in dir from
+// template, which must contain at least three consecutive 'X' characters.
+func (gs *GuestSession) CreateTempDirectory(template string, mode uint32, dir string, secure bool) (string, error) {
+	request := vboxweb.IGuestSessiondirectoryCreateTemp{
+		This:         gs.managedObjectId,
+		TemplateName: template,
+		Mode:         mode,
+		Path:         dir,
+		Secure:       secure,
+	}
+
+	response, err := gs.virtualbox.IGuestSessiondirectoryCreateTemp(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// RemoveDirectory removes the empty guest directory path.
+func (gs *GuestSession) RemoveDirectory(path string) error {
+	request := vboxweb.IGuestSessiondirectoryRemove{This: gs.managedObjectId, Path: path}
+
+	_, err := gs.virtualbox.IGuestSessiondirectoryRemove(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// RemoveDirectoryRecursive removes the contents of the guest directory path
+// and, unless contentOnly is true, the directory itself.
+func (gs *GuestSession) RemoveDirectoryRecursive(ctx context.Context, path string, contentOnly bool) error {
+	flag := vboxweb.DirectoryRemoveRecFlagContentAndDir
+	if contentOnly {
+		flag = vboxweb.DirectoryRemoveRecFlagContentOnly
+	}
+	request := vboxweb.IGuestSessiondirectoryRemoveRecursive{
+		This:  gs.managedObjectId,
+		Path:  path,
+		Flags: []*vboxweb.DirectoryRemoveRecFlag{&flag},
+	}
+
+	response, err := gs.virtualbox.IGuestSessiondirectoryRemoveRecursive(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return gs.waitProgress(ctx, response.Returnval)
+}
+
+// Stat returns information about the guest file system object path,
+// following symbolic links.
+func (gs *GuestSession) Stat(path string) (*GuestFileInfo, error) {
+	return gs.queryInfo(path, true)
+}
+
+// Lstat is like Stat but does not follow a final symbolic link.
+func (gs *GuestSession) Lstat(path string) (*GuestFileInfo, error) {
+	return gs.queryInfo(path, false)
+}
+
+func (gs *GuestSession) queryInfo(path string, followSymlinks bool) (*GuestFileInfo, error) {
+	request := vboxweb.IGuestSessionfsObjQueryInfo{This: gs.managedObjectId, Path: path, FollowSymlinks: followSymlinks}
+
+	response, err := gs.virtualbox.IGuestSessionfsObjQueryInfo(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	oi := &fsObjInfo{virtualbox: gs.virtualbox, managedObjectId: response.Returnval}
+	defer oi.release()
+
+	return oi.get()
+}
+
+// Exists reports whether the guest file system object path exists.
+func (gs *GuestSession) Exists(path string, followSymlinks bool) (bool, error) {
+	request := vboxweb.IGuestSessionfsObjExists{This: gs.managedObjectId, Path: path, FollowSymlinks: followSymlinks}
+
+	response, err := gs.virtualbox.IGuestSessionfsObjExists(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// Rename renames the guest file system object oldPath to newPath, replacing
+// an existing newPath only if replace is true.
+func (gs *GuestSession) Rename(oldPath, newPath string, replace bool) error {
+	flag := vboxweb.FsObjRenameFlagNoReplace
+	if replace {
+		flag = vboxweb.FsObjRenameFlagReplace
+	}
+	request := vboxweb.IGuestSessionfsObjRename{
+		This:    gs.managedObjectId,
+		OldPath: oldPath,
+		NewPath: newPath,
+		Flags:   []*vboxweb.FsObjRenameFlag{&flag},
+	}
+
+	_, err := gs.virtualbox.IGuestSessionfsObjRename(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Move moves the guest file system object source to destination, possibly
+// across file systems, and waits for the move to finish.
+func (gs *GuestSession) Move(ctx context.Context, source, destination string, flags []vboxweb.FsObjMoveFlags) error {
+	if len(flags) == 0 {
+		flags = []vboxweb.FsObjMoveFlags{vboxweb.FsObjMoveFlagsNone}
+	}
+	moveFlags := make([]*vboxweb.FsObjMoveFlags, len(flags))
+	for i := range flags {
+		moveFlags[i] = &flags[i]
+	}
+	request := vboxweb.IGuestSessionfsObjMove{
+		This:        gs.managedObjectId,
+		Source:      source,
+		Destination: destination,
+		Flags:       moveFlags,
+	}
+
+	response, err := gs.virtualbox.IGuestSessionfsObjMove(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return gs.waitProgress(ctx, response.Returnval)
+}
+
+// Remove removes the guest file or symbolic link path.
+func (gs *GuestSession) Remove(path string) error {
+	request := vboxweb.IGuestSessionfsObjRemove{This: gs.managedObjectId, Path: path}
+
+	_, err := gs.virtualbox.IGuestSessionfsObjRemove(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Symlink creates the guest symbolic link symlink pointing to target.
+func (gs *GuestSession) Symlink(target, symlink string, symlinkType vboxweb.SymlinkType) error {
+	request := vboxweb.IGuestSessionsymlinkCreate{
+		This:    gs.managedObjectId,
+		Symlink: symlink,
+		Target:  target,
+		Type_:   &symlinkType,
+	}
+
+	_, err := gs.virtualbox.IGuestSessionsymlinkCreate(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Readlink returns the target of the guest symbolic link symlink.
+func (gs *GuestSession) Readlink(symlink string) (string, error) {
+	flag := vboxweb.SymlinkReadFlagNone
+	request := vboxweb.IGuestSessionsymlinkRead{
+		This:    gs.managedObjectId,
+		Symlink: symlink,
+		Flags:   []*vboxweb.SymlinkReadFlag{&flag},
+	}
+
+	response, err := gs.virtualbox.IGuestSessionsymlinkRead(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (gs *GuestSession) SymlinkExists(symlink string) (bool, error) {
+	request := vboxweb.IGuestSessionsymlinkExists{This: gs.managedObjectId, Symlink: symlink}
+
+	response, err := gs.virtualbox.IGuestSessionsymlinkExists(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GuestDirectory is a directory opened in the guest for listing.
+type GuestDirectory struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// OpenDirectory opens the guest directory path for listing. filter is an
+// optional wildcard pattern the entries must match.
+func (gs *GuestSession) OpenDirectory(path, filter string) (*GuestDirectory, error) {
+	flag := vboxweb.DirectoryOpenFlagNone
+	request := vboxweb.IGuestSessiondirectoryOpen{
+		This:   gs.managedObjectId,
+		Path:   path,
+		Filter: filter,
+		Flags:  []*vboxweb.DirectoryOpenFlag{&flag},
+	}
+
+	response, err := gs.virtualbox.IGuestSessiondirectoryOpen(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &GuestDirectory{virtualbox: gs.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// Read returns the next entry of the directory, or io.EOF when there are no
+// more entries. The "." and ".." entries are returned as well.
+func (d *GuestDirectory) Read() (*GuestFileInfo, error) {
+	request := vboxweb.IDirectoryread{This: d.managedObjectId}
+
+	response, err := d.virtualbox.IDirectoryread(&request)
+	if err != nil {
+		if isObjectNotFound(err) {
+			return nil, io.EOF
+		}
+		return nil, err // TODO: Wrap the error
+	}
+
+	oi := &fsObjInfo{virtualbox: d.virtualbox, managedObjectId: response.Returnval}
+	defer oi.release()
+
+	return oi.get()
+}
+
+// Close closes the directory and releases the directory object, even if
+// closing fails.
+func (d *GuestDirectory) Close() error {
+	defer d.virtualbox.Release(d.managedObjectId)
+
+	request := vboxweb.IDirectoryclose{This: d.managedObjectId}
+
+	_, err := d.virtualbox.IDirectoryclose(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// ReadDir lists the guest directory path, skipping "." and "..".
+func (gs *GuestSession) ReadDir(path string) ([]*GuestFileInfo, error) {
+	d, err := gs.OpenDirectory(path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	var entries []*GuestFileInfo
+	for {
+		fi, err := d.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fi.FileName == "." || fi.FileName == ".." {
+			continue
+		}
+		entries = append(entries, fi)
+	}
+}
+
+// FS returns the guest file system below root as an fs.FS. The returned
+// file system also implements fs.ReadDirFS and fs.StatFS.
+func (gs *GuestSession) FS(root string) fs.FS {
+	return &guestFS{session: gs, root: root}
+}
+
+type guestFS struct {
+	session *GuestSession
+	root    string
+
+	// style is the path style of the guest, read on first use.
+	styleOnce sync.Once
+	style     vboxweb.PathStyle
+	styleErr  error
+}
+
+var (
+	_ fs.ReadDirFS = (*guestFS)(nil)
+	_ fs.StatFS    = (*guestFS)(nil)
+)
+
+func (gfs *guestFS) path(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	gfs.styleOnce.Do(func() {
+		gfs.style, gfs.styleErr = gfs.session.GetPathStyle()
+	})
+	if gfs.styleErr != nil {
+		return "", &fs.PathError{Op: op, Path: name, Err: gfs.styleErr}
+	}
+	return joinGuestPath(gfs.style, gfs.root, name), nil
+}
+
+func (gfs *guestFS) Open(name string) (fs.File, error) {
+	p, err := gfs.path("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := gfs.session.Stat(p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if name == "." {
+		fi.FileName = "."
+	}
+
+	if fi.IsDir() {
+		return &guestFSDir{fs: gfs, name: name, info: fi}, nil
+	}
+
+	f, err := gfs.session.OpenFile(p, vboxweb.FileAccessModeReadOnly, vboxweb.FileOpenActionOpenExisting, 0)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &guestFSFile{GuestFile: f, info: fi}, nil
+}
+
+func (gfs *guestFS) Stat(name string) (fs.FileInfo, error) {
+	p, err := gfs.path("stat", name)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := gfs.session.Stat(p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return fi, nil
+}
+
+func (gfs *guestFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	p, err := gfs.path("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+
+	infos, err := gfs.session.ReadDir(p)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	entries := make([]fs.DirEntry, len(infos))
+	for i, fi := range infos {
+		entries[i] = fi
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries, nil
+}
+
+type guestFSFile struct {
+	*GuestFile
+	info *GuestFileInfo
+}
+
+func (f *guestFSFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+type guestFSDir struct {
+	fs      *guestFS
+	name    string
+	info    *GuestFileInfo
+	entries []fs.DirEntry
+	read    bool
+}
+
+func (d *guestFSDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *guestFSDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *guestFSDir) Close() error { return nil }
+
+func (d *guestFSDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.read {
+		entries, err := d.fs.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.read = entries, true
+	}
+
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.entries) {
+		n = len(d.entries)
+	}
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
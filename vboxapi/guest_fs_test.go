@@ -0,0 +1,46 @@
+package vboxapi
+
+import (
+	"encoding/xml"
+	"io/fs"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestGuestFSPath(t *testing.T) {
+	tests := []struct {
+		style string
+		root  string
+		name  string
+		want  string
+	}{
+		{"UNIX", "/home/user", "dir/file.txt", "/home/user/dir/file.txt"},
+		{"UNIX", "/", ".", "/"},
+		{"DOS", `C:\`, "dir/file.txt", `C:\dir\file.txt`},
+		{"DOS", `C:\Users\user`, ".", `C:\Users\user`},
+		{"DOS", `\\server\share`, "file.txt", `\\server\share\file.txt`},
+	}
+
+	for _, tt := range tests {
+		f := newFakeVBox(t)
+		f.respond("IGuestSession_getPathStyle", "<returnval>"+tt.style+"</returnval>")
+		gs := &GuestSession{virtualbox: f.VirtualBox, managedObjectId: "session"}
+
+		// Only the path sent to the guest matters; the fake answers
+		// with an empty object.
+		gs.FS(tt.root).(fs.StatFS).Stat(tt.name)
+
+		reqs := f.requests("IGuestSession_fsObjQueryInfo")
+		if len(reqs) != 1 {
+			t.Fatalf("got %d fsObjQueryInfo requests, want 1", len(reqs))
+		}
+		var req vboxweb.IGuestSessionfsObjQueryInfo
+		if err := xml.Unmarshal(reqs[0], &req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Path != tt.want {
+			t.Errorf("%s FS(%q).Stat(%q) queried %q, want %q", tt.style, tt.root, tt.name, req.Path, tt.want)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package vboxapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// Handles of the standard streams of a guest process.
+const (
+	guestStdin  uint32 = 0
+	guestStdout uint32 = 1
+	guestStderr uint32 = 2
+)
+
+// guestProcessPollTimeout is how long, in milliseconds, each wait for
+// process output blocks before the process status is checked again.
+const guestProcessPollTimeout = 500
+
+// guestProcessReadSize is the maximum number of bytes read from a guest
+// process stream at once.
+const guestProcessReadSize = 64 * 1024
+
+// GuestProcessError is returned when a guest process did not start or did
+// not terminate normally.
+type GuestProcessError struct {
+	Status vboxweb.ProcessStatus
+}
+
+func (e *GuestProcessError) Error() string {
+	return fmt.Sprintf("guest process failed: %s", e.Status)
+}
+
+// GuestProcess is a process started in the guest by GuestSession.Exec.
+type GuestProcess struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+
+	// Stdin is connected to the standard input of the process. Closing it
+	// sends end-of-file to the process.
+	Stdin io.WriteCloser
+	// Stdout and Stderr stream the output of the process. The output is
+	// buffered, so the process is not held up by a slow reader.
+	Stdout io.Reader
+	Stderr io.Reader
+
+	done     chan struct{}
+	exitCode int32
+	err      error
+}
+
+func (p *GuestProcess) start(ctx context.Context) {
+	stdinR, stdinW := io.Pipe()
+	stdout, stderr := newGuestOutput(), newGuestOutput()
+	p.Stdin, p.Stdout, p.Stderr = stdinW, stdout, stderr
+	p.done = make(chan struct{})
+
+	var stdinErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		stdinErr = p.pumpStdin(stdinR)
+	}()
+
+	go func() {
+		p.exitCode, p.err = p.pumpOutput(ctx, stdout, stderr)
+		stdout.closeWithError(p.err)
+		stderr.closeWithError(p.err)
+		stdinR.CloseWithError(io.ErrClosedPipe)
+		wg.Wait()
+		if p.err == nil {
+			p.err = stdinErr
+		}
+		close(p.done)
+	}()
+}
+
+// pumpStdin copies r to the standard input of the process and sends
+// end-of-file once r is closed. It returns the first error writing to the
+// process.
+func (p *GuestProcess) pumpStdin(r *io.PipeReader) error {
+	buf := make([]byte, guestProcessReadSize)
+	for {
+		n, err := r.Read(buf)
+		if werr := p.writeStdin(buf[:n]); werr != nil {
+			r.CloseWithError(werr)
+			return werr
+		}
+		if err == io.EOF {
+			_, werr := p.write(guestStdin, nil, true)
+			return werr
+		}
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// writeStdin writes data to the standard input of the process, retrying
+// until the guest has accepted all of it.
+func (p *GuestProcess) writeStdin(data []byte) error {
+	for len(data) > 0 {
+		n, err := p.write(guestStdin, data, false)
+		if err != nil {
+			return err
+		}
+		if int(n) > len(data) {
+			n = uint32(len(data))
+		}
+		data = data[n:]
+	}
+	return nil
+}
+
+func (p *GuestProcess) pumpOutput(ctx context.Context, stdout, stderr io.Writer) (int32, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			p.Terminate()
+			return -1, ctx.Err()
+		default:
+		}
+
+		if _, err := p.waitFor(guestProcessPollTimeout,
+			vboxweb.ProcessWaitForFlagStdOut,
+			vboxweb.ProcessWaitForFlagStdErr,
+			vboxweb.ProcessWaitForFlagTerminate); err != nil {
+			return -1, err
+		}
+
+		status, err := p.GetStatus()
+		if err != nil {
+			return -1, err
+		}
+
+		if err := p.drain(guestStdout, stdout); err != nil {
+			return -1, err
+		}
+		if err := p.drain(guestStderr, stderr); err != nil {
+			return -1, err
+		}
+
+		switch status {
+		case vboxweb.ProcessStatusStarting, vboxweb.ProcessStatusStarted,
+			vboxweb.ProcessStatusPaused, vboxweb.ProcessStatusTerminating:
+			continue
+		case vboxweb.ProcessStatusTerminatedNormally:
+			return p.GetExitCode()
+		default:
+			return -1, &GuestProcessError{Status: status}
+		}
+	}
+}
+
+// guestOutput is an unbounded in-memory pipe carrying the output of a guest
+// process. Writes never block, so the process is polled and terminated on
+// time whether or not the output is read.
+type guestOutput struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  bytes.Buffer
+	err  error
+}
+
+func newGuestOutput() *guestOutput {
+	o := &guestOutput{}
+	o.cond = sync.NewCond(&o.mu)
+	return o
+}
+
+func (o *guestOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.cond.Broadcast()
+	return o.buf.Write(p)
+}
+
+func (o *guestOutput) Read(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for o.buf.Len() == 0 && o.err == nil {
+		o.cond.Wait()
+	}
+	if o.buf.Len() > 0 {
+		return o.buf.Read(p)
+	}
+	return 0, o.err
+}
+
+// closeWithError makes reads return err, or io.EOF if err is nil, once the
+// buffered output is consumed.
+func (o *guestOutput) closeWithError(err error) {
+	if err == nil {
+		err = io.EOF
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.err = err
+	o.cond.Broadcast()
+}
+
+// drain copies everything currently buffered on handle to w.
+func (p *GuestProcess) drain(handle uint32, w io.Writer) error {
+	for {
+		data, err := p.read(handle, guestProcessReadSize, 0)
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *GuestProcess) read(handle, toRead, timeoutMS uint32) ([]byte, error) {
+	request := vboxweb.IProcessread{This: p.managedObjectId, Handle: handle, ToRead: toRead, TimeoutMS: timeoutMS}
+
+	response, err := p.virtualbox.IProcessread(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return base64.StdEncoding.DecodeString(response.Returnval)
+}
+
+func (p *GuestProcess) write(handle uint32, data []byte, eof bool) (uint32, error) {
+	flag := vboxweb.ProcessInputFlagNone
+	if eof {
+		flag = vboxweb.ProcessInputFlagEndOfFile
+	}
+	request := vboxweb.IProcesswriteArray{
+		This:      p.managedObjectId,
+		Handle:    handle,
+		Flags:     []*vboxweb.ProcessInputFlag{&flag},
+		Data:      base64.StdEncoding.EncodeToString(data),
+		TimeoutMS: guestProcessPollTimeout,
+	}
+
+	response, err := p.virtualbox.IProcesswriteArray(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (p *GuestProcess) waitFor(timeoutMS uint32, flags ...vboxweb.ProcessWaitForFlag) (vboxweb.ProcessWaitResult, error) {
+	waitFor := make([]*vboxweb.ProcessWaitForFlag, len(flags))
+	for i := range flags {
+		waitFor[i] = &flags[i]
+	}
+	request := vboxweb.IProcesswaitForArray{This: p.managedObjectId, WaitFor: waitFor, TimeoutMS: timeoutMS}
+
+	response, err := p.virtualbox.IProcesswaitForArray(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	if response.Returnval == nil {
+		return vboxweb.ProcessWaitResultNone, nil
+	}
+	return *response.Returnval, nil
+}
+
+// Wait waits for the process to exit and its output to be consumed, then
+// releases the process object. It must be called exactly once. A failure
+// to write the standard input of the process is reported as well.
+func (p *GuestProcess) Wait() (int32, error) {
+	<-p.done
+	p.Release()
+	return p.exitCode, p.err
+}
+
+func (p *GuestProcess) GetPID() (uint32, error) {
+	request := vboxweb.IProcessgetPID{This: p.managedObjectId}
+
+	response, err := p.virtualbox.IProcessgetPID(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (p *GuestProcess) GetStatus() (vboxweb.ProcessStatus, error) {
+	request := vboxweb.IProcessgetStatus{This: p.managedObjectId}
+
+	response, err := p.virtualbox.IProcessgetStatus(&request)
+	if err != nil {
+		return vboxweb.ProcessStatusUndefined, err // TODO: Wrap the error
+	}
+
+	if response.Returnval == nil {
+		return vboxweb.ProcessStatusUndefined, nil
+	}
+	return *response.Returnval, nil
+}
+
+func (p *GuestProcess) GetExitCode() (int32, error) {
+	request := vboxweb.IProcessgetExitCode{This: p.managedObjectId}
+
+	response, err := p.virtualbox.IProcessgetExitCode(&request)
+	if err != nil {
+		return -1, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (p *GuestProcess) Terminate() error {
+	request := vboxweb.IProcessterminate{This: p.managedObjectId}
+
+	_, err := p.virtualbox.IProcessterminate(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (p *GuestProcess) Release() error {
+	return p.virtualbox.Release(p.managedObjectId)
+}
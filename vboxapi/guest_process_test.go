@@ -0,0 +1,74 @@
+package vboxapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestGuestProcessStdinPartialWrites(t *testing.T) {
+	f := newFakeVBox(t)
+	f.respond("IGuestSession_processCreateEx", "<returnval>process</returnval>")
+	f.respond("IProcess_waitForArray", "<returnval>Start</returnval>", "<returnval>Timeout</returnval>")
+	f.respond("IProcess_getStatus", "<returnval>Started</returnval>")
+	f.respond("IProcess_read", "<returnval></returnval>")
+	f.respond("IProcess_getExitCode", "<returnval>0</returnval>")
+	// The guest takes three bytes of the first write, then everything.
+	f.respond("IProcess_writeArray", "<returnval>3</returnval>", "<returnval>65536</returnval>")
+	gs := &GuestSession{virtualbox: f.VirtualBox, managedObjectId: "session"}
+
+	p, err := gs.Exec(context.Background(), "/bin/cat", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Stdin.Write([]byte("hello world")); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Stdin.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var writes []vboxweb.IProcesswriteArray
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		writes = writes[:0]
+		for _, req := range f.requests("IProcess_writeArray") {
+			var w vboxweb.IProcesswriteArray
+			if err := xml.Unmarshal(req, &w); err != nil {
+				t.Fatal(err)
+			}
+			writes = append(writes, w)
+		}
+		if n := len(writes); n > 0 && len(writes[n-1].Flags) > 0 && *writes[n-1].Flags[0] == vboxweb.ProcessInputFlagEndOfFile {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("no end-of-file written after %d writes", len(writes))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	f.respond("IProcess_getStatus", "<returnval>TerminatedNormally</returnval>")
+
+	if code, err := p.Wait(); code != 0 || err != nil {
+		t.Errorf("Wait() = %d, %v, want 0, nil", code, err)
+	}
+
+	var sent string
+	for _, w := range writes {
+		data, err := base64.StdEncoding.DecodeString(w.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent += string(data)
+	}
+	// The first write is partly accepted, so its tail is written again.
+	if want := "hello world" + "lo world"; sent != want {
+		t.Errorf("written data = %q, want %q", sent, want)
+	}
+	if len(writes) != 3 {
+		t.Errorf("got %d writes, want 3", len(writes))
+	}
+}
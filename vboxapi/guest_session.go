@@ -0,0 +1,137 @@
+package vboxapi
+
+import (
+	"context"
+	"time"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// GuestSession is a session of a user in the guest operating system.
+type GuestSession struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// ExecOptions holds optional settings for GuestSession.Exec.
+type ExecOptions struct {
+	// Timeout is the time after which the guest additions kill the process.
+	// Zero means no timeout.
+	Timeout time.Duration
+	// Flags are extra process creation flags.
+	Flags []vboxweb.ProcessCreateFlag
+}
+
+func (gs *GuestSession) waitFor(timeoutMS uint32, flags ...vboxweb.GuestSessionWaitForFlag) (vboxweb.GuestSessionWaitResult, error) {
+	waitFor := make([]*vboxweb.GuestSessionWaitForFlag, len(flags))
+	for i := range flags {
+		waitFor[i] = &flags[i]
+	}
+	request := vboxweb.IGuestSessionwaitForArray{This: gs.managedObjectId, WaitFor: waitFor, TimeoutMS: timeoutMS}
+
+	response, err := gs.virtualbox.IGuestSessionwaitForArray(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	if response.Returnval == nil {
+		return vboxweb.GuestSessionWaitResultNone, nil
+	}
+	return *response.Returnval, nil
+}
+
+// Exec starts cmd with args in the guest. env holds "NAME=VALUE" changes to
+// the session environment. The process is terminated when ctx is done.
+//
+// The output of the process is buffered in memory until it is read from
+// Stdout and Stderr.
+func (gs *GuestSession) Exec(ctx context.Context, cmd string, args, env []string, opts *ExecOptions) (*GuestProcess, error) {
+	if opts == nil {
+		opts = &ExecOptions{}
+	}
+
+	flags := []vboxweb.ProcessCreateFlag{vboxweb.ProcessCreateFlagWaitForStdOut, vboxweb.ProcessCreateFlagWaitForStdErr}
+	flags = append(flags, opts.Flags...)
+	createFlags := make([]*vboxweb.ProcessCreateFlag, len(flags))
+	for i := range flags {
+		createFlags[i] = &flags[i]
+	}
+
+	priority := vboxweb.ProcessPriorityDefault
+	request := vboxweb.IGuestSessionprocessCreateEx{
+		This:               gs.managedObjectId,
+		Executable:         cmd,
+		Arguments:          append([]string{cmd}, args...),
+		EnvironmentChanges: env,
+		Flags:              createFlags,
+		TimeoutMS:          uint32(opts.Timeout / time.Millisecond),
+		Priority:           &priority,
+	}
+
+	response, err := gs.virtualbox.IGuestSessionprocessCreateEx(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	p := &GuestProcess{virtualbox: gs.virtualbox, managedObjectId: response.Returnval}
+
+	result, err := p.waitFor(guestSessionStartTimeout, vboxweb.ProcessWaitForFlagStart)
+	if err != nil {
+		p.Release()
+		return nil, err
+	}
+	switch result {
+	case vboxweb.ProcessWaitResultStart, vboxweb.ProcessWaitResultTerminate, vboxweb.ProcessWaitResultStatus:
+		// The process started, possibly exiting already; its output and
+		// exit status are collected by the pumps.
+	default:
+		status, _ := p.GetStatus()
+		p.Release()
+		return nil, &GuestProcessError{Status: status}
+	}
+
+	p.start(ctx)
+	return p, nil
+}
+
+// Close closes the guest session, terminating processes still running in
+// it, and releases the session object, even if closing fails.
+func (gs *GuestSession) Close() error {
+	defer gs.Release()
+
+	request := vboxweb.IGuestSessionclose{This: gs.managedObjectId}
+
+	_, err := gs.virtualbox.IGuestSessionclose(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// GetEventSource returns the event source of the guest session, which delivers
+// guest process and file events of the session.
+func (gs *GuestSession) GetEventSource() (*EventSource, error) {
+	request := vboxweb.IGuestSessiongetEventSource{This: gs.managedObjectId}
+
+	response, err := gs.virtualbox.IGuestSessiongetEventSource(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &EventSource{virtualbox: gs.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// Events subscribes to the event source of the guest session. See
+// EventSource.Events.
+func (gs *GuestSession) Events(ctx context.Context, types ...vboxweb.VBoxEventType) (<-chan Event, error) {
+	es, err := gs.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.events(ctx, true, types)
+}
+
+func (gs *GuestSession) Release() error {
+	return gs.virtualbox.Release(gs.managedObjectId)
+}
@@ -0,0 +1,61 @@
+package vboxapi
+
+import (
+	"context"
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// respondExitedProcess makes the fake answer as for a process that exits
+// right away with no output.
+func respondExitedProcess(f *fakeVBox) {
+	f.respond("IGuestSession_processCreateEx", "<returnval>process</returnval>")
+	f.respond("IProcess_waitForArray", "<returnval>Terminate</returnval>")
+	f.respond("IProcess_getStatus", "<returnval>TerminatedNormally</returnval>")
+	f.respond("IProcess_read", "<returnval></returnval>")
+	f.respond("IProcess_getExitCode", "<returnval>0</returnval>")
+}
+
+func TestExecArguments(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		args []string
+		want []string
+	}{
+		{"/bin/ls", nil, []string{"/bin/ls"}},
+		{"/bin/ls", []string{"-l", "/tmp"}, []string{"/bin/ls", "-l", "/tmp"}},
+		{`C:\Windows\System32\cmd.exe`, []string{"/c", "dir"}, []string{`C:\Windows\System32\cmd.exe`, "/c", "dir"}},
+	}
+
+	for _, tt := range tests {
+		f := newFakeVBox(t)
+		respondExitedProcess(f)
+		gs := &GuestSession{virtualbox: f.VirtualBox, managedObjectId: "session"}
+
+		p, err := gs.Exec(context.Background(), tt.cmd, tt.args, nil, nil)
+		if err != nil {
+			t.Fatalf("Exec(%q, %q) error = %v", tt.cmd, tt.args, err)
+		}
+		if code, err := p.Wait(); code != 0 || err != nil {
+			t.Errorf("Exec(%q, %q).Wait() = %d, %v, want 0, nil", tt.cmd, tt.args, code, err)
+		}
+
+		reqs := f.requests("IGuestSession_processCreateEx")
+		if len(reqs) != 1 {
+			t.Fatalf("Exec(%q, %q) sent %d processCreateEx requests, want 1", tt.cmd, tt.args, len(reqs))
+		}
+		var request vboxweb.IGuestSessionprocessCreateEx
+		if err := xml.Unmarshal(reqs[0], &request); err != nil {
+			t.Fatal(err)
+		}
+		if request.Executable != tt.cmd {
+			t.Errorf("Exec(%q, %q) executable = %q, want %q", tt.cmd, tt.args, request.Executable, tt.cmd)
+		}
+		if !reflect.DeepEqual(request.Arguments, tt.want) {
+			t.Errorf("Exec(%q, %q) arguments = %q, want %q", tt.cmd, tt.args, request.Arguments, tt.want)
+		}
+	}
+}
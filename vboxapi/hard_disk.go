@@ -17,6 +17,16 @@ func isSet(value string) bool {
 	return value != ""
 }
 
+// Media returns the hard disks as media whose fields are not read yet; see
+// Medium.Get.
+func (hs *HardDisks) Media() []*Medium {
+	media := make([]*Medium, len(hs.disks))
+	for i, hardDisk := range hs.disks {
+		media[i] = hardDisk.getMedium()
+	}
+	return media
+}
+
 func (hs *HardDisks) GetMedium(objectID, name string) ([]*Medium, error) {
 	var ms []*Medium
 	for _, hardDisk := range hs.disks {
@@ -0,0 +1,131 @@
+package vboxapi
+
+import (
+	"context"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// Host is the physical machine VirtualBox runs on.
+type Host struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// newHostNetworkInterface reads the interface oid, releasing it if that
+// fails.
+func (h *Host) newHostNetworkInterface(oid string) (*HostNetworkInterface, error) {
+	hni := &HostNetworkInterface{virtualbox: h.virtualbox, managedObjectId: oid}
+	if _, err := hni.Get(); err != nil {
+		hni.Release()
+		return nil, err
+	}
+	return hni, nil
+}
+
+func (h *Host) newHostNetworkInterfaces(oids []string) ([]*HostNetworkInterface, error) {
+	interfaces := make([]*HostNetworkInterface, 0, len(oids))
+	for i, oid := range oids {
+		hni, err := h.newHostNetworkInterface(oid)
+		if err != nil {
+			for _, hni := range interfaces {
+				hni.Release()
+			}
+			for _, oid := range oids[i+1:] {
+				h.virtualbox.Release(oid)
+			}
+			return nil, err
+		}
+		interfaces = append(interfaces, hni)
+	}
+
+	return interfaces, nil
+}
+
+func (h *Host) GetNetworkInterfaces() ([]*HostNetworkInterface, error) {
+	request := vboxweb.IHostgetNetworkInterfaces{This: h.managedObjectId}
+
+	response, err := h.virtualbox.IHostgetNetworkInterfaces(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return h.newHostNetworkInterfaces(response.Returnval)
+}
+
+func (h *Host) FindHostNetworkInterfaceByName(name string) (*HostNetworkInterface, error) {
+	request := vboxweb.IHostfindHostNetworkInterfaceByName{This: h.managedObjectId, Name: name}
+
+	response, err := h.virtualbox.IHostfindHostNetworkInterfaceByName(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return h.newHostNetworkInterface(response.Returnval)
+}
+
+func (h *Host) FindHostNetworkInterfaceByID(id string) (*HostNetworkInterface, error) {
+	request := vboxweb.IHostfindHostNetworkInterfaceById{This: h.managedObjectId, Id: id}
+
+	response, err := h.virtualbox.IHostfindHostNetworkInterfaceById(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return h.newHostNetworkInterface(response.Returnval)
+}
+
+func (h *Host) FindHostNetworkInterfacesOfType(interfaceType vboxweb.HostNetworkInterfaceType) ([]*HostNetworkInterface, error) {
+	request := vboxweb.IHostfindHostNetworkInterfacesOfType{This: h.managedObjectId, Type_: &interfaceType}
+
+	response, err := h.virtualbox.IHostfindHostNetworkInterfacesOfType(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return h.newHostNetworkInterfaces(response.Returnval)
+}
+
+// CreateHostOnlyNetworkInterface creates a new host-only interface and waits
+// for the creation to complete.
+func (h *Host) CreateHostOnlyNetworkInterface() (*HostNetworkInterface, error) {
+	request := vboxweb.IHostcreateHostOnlyNetworkInterface{This: h.managedObjectId}
+
+	response, err := h.virtualbox.IHostcreateHostOnlyNetworkInterface(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	progress := &Progress{virtualbox: h.virtualbox, managedObjectId: response.Returnval}
+	defer progress.Release()
+
+	if err := progress.Wait(context.Background(), nil); err != nil {
+		return nil, err
+	}
+
+	return h.newHostNetworkInterface(response.HostInterface)
+}
+
+// RemoveHostOnlyNetworkInterface removes the host-only interface with the
+// given ID and waits for the removal to complete.
+func (h *Host) RemoveHostOnlyNetworkInterface(id string) error {
+	request := vboxweb.IHostremoveHostOnlyNetworkInterface{This: h.managedObjectId, Id: id}
+
+	response, err := h.virtualbox.IHostremoveHostOnlyNetworkInterface(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	progress := &Progress{virtualbox: h.virtualbox, managedObjectId: response.Returnval}
+	defer progress.Release()
+
+	if err := progress.Wait(context.Background(), nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (h *Host) Release() error {
+	return h.virtualbox.Release(h.managedObjectId)
+}
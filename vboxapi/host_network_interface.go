@@ -0,0 +1,321 @@
+package vboxapi
+
+import "github.com/blacktop/go-vboxapi/vboxweb"
+
+// HostNetworkInterface is a network interface of the VirtualBox host, either
+// a physical interface usable for bridging or a host-only interface.
+type HostNetworkInterface struct {
+	virtualbox                  *VirtualBox
+	managedObjectId             string
+	Name                        string
+	ShortName                   string
+	ID                          string
+	NetworkName                 string
+	DHCPEnabled                 bool
+	IPAddress                   string
+	NetworkMask                 string
+	IPV6Supported               bool
+	IPV6Address                 string
+	IPV6NetworkMaskPrefixLength uint32
+	HardwareAddress             string
+	MediumType                  vboxweb.HostNetworkInterfaceMediumType
+	Status                      vboxweb.HostNetworkInterfaceStatus
+	InterfaceType               vboxweb.HostNetworkInterfaceType
+}
+
+func (hni *HostNetworkInterface) GetName() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetName{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetName(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetShortName() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetShortName{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetShortName(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetID() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetId{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetId(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetNetworkName() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetNetworkName{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetNetworkName(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetDHCPEnabled() (bool, error) {
+	request := vboxweb.IHostNetworkInterfacegetDHCPEnabled{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetDHCPEnabled(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetIPAddress() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetIPAddress{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetIPAddress(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetNetworkMask() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetNetworkMask{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetNetworkMask(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetIPV6Supported() (bool, error) {
+	request := vboxweb.IHostNetworkInterfacegetIPV6Supported{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetIPV6Supported(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetIPV6Address() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetIPV6Address{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetIPV6Address(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetIPV6NetworkMaskPrefixLength() (uint32, error) {
+	request := vboxweb.IHostNetworkInterfacegetIPV6NetworkMaskPrefixLength{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetIPV6NetworkMaskPrefixLength(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetHardwareAddress() (string, error) {
+	request := vboxweb.IHostNetworkInterfacegetHardwareAddress{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetHardwareAddress(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetMediumType() (*vboxweb.HostNetworkInterfaceMediumType, error) {
+	request := vboxweb.IHostNetworkInterfacegetMediumType{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetMediumType(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetStatus() (*vboxweb.HostNetworkInterfaceStatus, error) {
+	request := vboxweb.IHostNetworkInterfacegetStatus{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetStatus(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (hni *HostNetworkInterface) GetInterfaceType() (*vboxweb.HostNetworkInterfaceType, error) {
+	request := vboxweb.IHostNetworkInterfacegetInterfaceType{This: hni.managedObjectId}
+
+	response, err := hni.virtualbox.IHostNetworkInterfacegetInterfaceType(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// EnableStaticIPConfig assigns a static IPv4 address to a host-only
+// interface.
+func (hni *HostNetworkInterface) EnableStaticIPConfig(ipAddress, networkMask string) error {
+	request := vboxweb.IHostNetworkInterfaceenableStaticIPConfig{This: hni.managedObjectId, IPAddress: ipAddress, NetworkMask: networkMask}
+
+	_, err := hni.virtualbox.IHostNetworkInterfaceenableStaticIPConfig(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// EnableStaticIPConfigV6 assigns a static IPv6 address to a host-only
+// interface.
+func (hni *HostNetworkInterface) EnableStaticIPConfigV6(ipv6Address string, prefixLength uint32) error {
+	request := vboxweb.IHostNetworkInterfaceenableStaticIPConfigV6{
+		This:                        hni.managedObjectId,
+		IPV6Address:                 ipv6Address,
+		IPV6NetworkMaskPrefixLength: prefixLength,
+	}
+
+	_, err := hni.virtualbox.IHostNetworkInterfaceenableStaticIPConfigV6(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (hni *HostNetworkInterface) EnableDynamicIPConfig() error {
+	request := vboxweb.IHostNetworkInterfaceenableDynamicIPConfig{This: hni.managedObjectId}
+
+	_, err := hni.virtualbox.IHostNetworkInterfaceenableDynamicIPConfig(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (hni *HostNetworkInterface) DHCPRediscover() error {
+	request := vboxweb.IHostNetworkInterfaceDHCPRediscover{This: hni.managedObjectId}
+
+	_, err := hni.virtualbox.IHostNetworkInterfaceDHCPRediscover(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (hni *HostNetworkInterface) Release() error {
+	return hni.virtualbox.Release(hni.managedObjectId)
+}
+
+// Get reads all attributes of the interface into its fields.
+func (hni *HostNetworkInterface) Get() (*HostNetworkInterface, error) {
+	var err error
+	hni.Name, err = hni.GetName()
+	if err != nil {
+		return nil, err
+	}
+
+	hni.ShortName, err = hni.GetShortName()
+	if err != nil {
+		return nil, err
+	}
+
+	hni.ID, err = hni.GetID()
+	if err != nil {
+		return nil, err
+	}
+
+	hni.NetworkName, err = hni.GetNetworkName()
+	if err != nil {
+		return nil, err
+	}
+
+	hni.DHCPEnabled, err = hni.GetDHCPEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	hni.IPAddress, err = hni.GetIPAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	hni.NetworkMask, err = hni.GetNetworkMask()
+	if err != nil {
+		return nil, err
+	}
+
+	hni.IPV6Supported, err = hni.GetIPV6Supported()
+	if err != nil {
+		return nil, err
+	}
+
+	if hni.IPV6Supported {
+		hni.IPV6Address, err = hni.GetIPV6Address()
+		if err != nil {
+			return nil, err
+		}
+
+		hni.IPV6NetworkMaskPrefixLength, err = hni.GetIPV6NetworkMaskPrefixLength()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hni.HardwareAddress, err = hni.GetHardwareAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	mt, err := hni.GetMediumType()
+	if err != nil {
+		return nil, err
+	}
+	if mt != nil {
+		hni.MediumType = *mt
+	}
+
+	st, err := hni.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	if st != nil {
+		hni.Status = *st
+	}
+
+	it, err := hni.GetInterfaceType()
+	if err != nil {
+		return nil, err
+	}
+	if it != nil {
+		hni.InterfaceType = *it
+	}
+
+	return hni, nil
+}
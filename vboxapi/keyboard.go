@@ -0,0 +1,337 @@
+package vboxapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// Keyboard is the virtual keyboard of a running VM.
+type Keyboard struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+
+	// Layout translates the characters passed to Type. It defaults to
+	// LayoutUS.
+	Layout KeyboardLayout
+	// KeyInterval is the pause between keystrokes sent by Type and Press.
+	KeyInterval time.Duration
+}
+
+// namedKeys maps the lower-cased key names accepted by Press, including the
+// names used in boot commands, to their make codes.
+var namedKeys = map[string]KeyCode{
+	"esc": keyEsc, "escape": keyEsc,
+	"bs": keyBackspace, "backspace": keyBackspace,
+	"tab":   keyTab,
+	"enter": keyEnter, "return": keyEnter,
+	"space": keySpace, "spacebar": keySpace,
+	"ctrl": keyLeftCtrl, "lctrl": keyLeftCtrl, "leftctrl": keyLeftCtrl,
+	"rctrl": keyRightCtrl, "rightctrl": keyRightCtrl,
+	"shift": keyLeftShift, "lshift": keyLeftShift, "leftshift": keyLeftShift,
+	"rshift": keyRightShift, "rightshift": keyRightShift,
+	"alt": keyLeftAlt, "lalt": keyLeftAlt, "leftalt": keyLeftAlt,
+	"ralt": keyRightAlt, "rightalt": keyRightAlt, "altgr": keyRightAlt,
+	"super": keyLeftSuper, "win": keyLeftSuper, "lwin": keyLeftSuper, "leftsuper": keyLeftSuper,
+	"rwin": keyRightSuper, "rightsuper": keyRightSuper,
+	"menu":     keyMenu,
+	"capslock": keyCapsLock, "numlock": keyNumLock, "scrolllock": keyScrollLock,
+	"home": keyHome, "end": keyEnd,
+	"pageup": keyPageUp, "pgup": keyPageUp,
+	"pagedown": keyPageDown, "pgdn": keyPageDown,
+	"insert": keyInsert, "ins": keyInsert,
+	"delete": keyDelete, "del": keyDelete,
+	"up": keyUp, "down": keyDown, "left": keyLeft, "right": keyRight,
+	"f11": keyF11, "f12": keyF12,
+}
+
+func init() {
+	for i := 0; i < 10; i++ {
+		namedKeys[fmt.Sprintf("f%d", i+1)] = keyF1 + KeyCode(i)
+	}
+}
+
+// Keyboard returns the keyboard of the console's VM.
+func (c *Console) Keyboard() (*Keyboard, error) {
+	request := vboxweb.IConsolegetKeyboard{This: c.managedObjectID}
+
+	response, err := c.virtualbox.IConsolegetKeyboard(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Keyboard{virtualbox: c.virtualbox, managedObjectId: response.Returnval, Layout: LayoutUS}, nil
+}
+
+func (k *Keyboard) PutScancode(scancode int32) error {
+	request := vboxweb.IKeyboardputScancode{This: k.managedObjectId, Scancode: scancode}
+
+	_, err := k.virtualbox.IKeyboardputScancode(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// PutScancodes sends scancodes to the keyboard.
+func (k *Keyboard) PutScancodes(scancodes []int32) (uint32, error) {
+	request := vboxweb.IKeyboardputScancodes{This: k.managedObjectId, Scancodes: scancodes}
+
+	response, err := k.virtualbox.IKeyboardputScancodes(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// PutCAD sends the Ctrl+Alt+Del key combination.
+func (k *Keyboard) PutCAD() error {
+	request := vboxweb.IKeyboardputCAD{This: k.managedObjectId}
+
+	_, err := k.virtualbox.IKeyboardputCAD(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// ReleaseKeys releases all keys currently held down.
+func (k *Keyboard) ReleaseKeys() error {
+	request := vboxweb.IKeyboardreleaseKeys{This: k.managedObjectId}
+
+	_, err := k.virtualbox.IKeyboardreleaseKeys(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// GetLEDs returns the keyboard LEDs that are lit.
+func (k *Keyboard) GetLEDs() ([]vboxweb.KeyboardLED, error) {
+	request := vboxweb.IKeyboardgetKeyboardLEDs{This: k.managedObjectId}
+
+	response, err := k.virtualbox.IKeyboardgetKeyboardLEDs(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	leds := make([]vboxweb.KeyboardLED, 0, len(response.Returnval))
+	for _, led := range response.Returnval {
+		if led != nil {
+			leds = append(leds, *led)
+		}
+	}
+
+	return leds, nil
+}
+
+// GetEventSource returns the event source of the keyboard, which delivers
+// keyboard LED and guest keyboard events.
+func (k *Keyboard) GetEventSource() (*EventSource, error) {
+	request := vboxweb.IKeyboardgetEventSource{This: k.managedObjectId}
+
+	response, err := k.virtualbox.IKeyboardgetEventSource(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &EventSource{virtualbox: k.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// Events subscribes to the event source of the keyboard. See
+// EventSource.Events.
+func (k *Keyboard) Events(ctx context.Context, types ...vboxweb.VBoxEventType) (<-chan Event, error) {
+	es, err := k.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.events(ctx, true, types)
+}
+
+func (k *Keyboard) Release() error {
+	return k.virtualbox.Release(k.managedObjectId)
+}
+
+// put sends scancodes and waits KeyInterval, returning early if ctx is
+// done.
+func (k *Keyboard) put(ctx context.Context, scancodes []int32) error {
+	if len(scancodes) == 0 {
+		return nil
+	}
+	n, err := k.PutScancodes(scancodes)
+	if err != nil {
+		return err
+	}
+	if int(n) < len(scancodes) {
+		return fmt.Errorf("keyboard buffer full: %d of %d scancodes stored", n, len(scancodes))
+	}
+	if k.KeyInterval <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(k.KeyInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// typeScancodes translates text into make and break scancodes using layout,
+// one group per character.
+func typeScancodes(layout KeyboardLayout, text string) ([][]int32, error) {
+	groups := make([][]int32, 0, utf8.RuneCountInString(text))
+	for _, r := range text {
+		ks, ok := layout[r]
+		if !ok {
+			return nil, fmt.Errorf("character %q is not in the keyboard layout", r)
+		}
+
+		var codes []int32
+		if ks.Shift {
+			codes = append(codes, keyLeftShift.makeCodes()...)
+		}
+		if ks.AltGr {
+			codes = append(codes, keyRightAlt.makeCodes()...)
+		}
+		codes = append(codes, ks.Code.makeCodes()...)
+		codes = append(codes, ks.Code.breakCodes()...)
+		if ks.AltGr {
+			codes = append(codes, keyRightAlt.breakCodes()...)
+		}
+		if ks.Shift {
+			codes = append(codes, keyLeftShift.breakCodes()...)
+		}
+		if ks.Dead {
+			codes = append(codes, keySpace.makeCodes()...)
+			codes = append(codes, keySpace.breakCodes()...)
+		}
+		groups = append(groups, codes)
+	}
+	return groups, nil
+}
+
+// Type types text on the keyboard using its Layout, stopping when ctx is
+// done.
+func (k *Keyboard) Type(ctx context.Context, text string) error {
+	layout := k.Layout
+	if layout == nil {
+		layout = LayoutUS
+	}
+
+	groups, err := typeScancodes(layout, text)
+	if err != nil {
+		return err
+	}
+
+	for _, codes := range groups {
+		if err := k.put(ctx, codes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseKey returns the make codes of the key named name, which is either a
+// name from namedKeys or a single character of layout. A character typed
+// with Shift or AltGr is preceded by the make code of that modifier.
+func parseKey(layout KeyboardLayout, name string) ([]KeyCode, error) {
+	if code, ok := namedKeys[strings.ToLower(name)]; ok {
+		return []KeyCode{code}, nil
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		if ks, ok := layout[r]; ok {
+			var codes []KeyCode
+			if ks.Shift {
+				codes = append(codes, keyLeftShift)
+			}
+			if ks.AltGr {
+				codes = append(codes, keyRightAlt)
+			}
+			return append(codes, ks.Code), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key %q", name)
+}
+
+// splitCombo splits a key combination into key names. A '+' directly
+// after a separator, or at the start, is the name of the '+' key.
+func splitCombo(combo string) []string {
+	var names []string
+	for {
+		i := -1
+		if combo != "" {
+			i = strings.IndexByte(combo[1:], '+')
+		}
+		if i < 0 {
+			return append(names, combo)
+		}
+		names = append(names, combo[:i+1])
+		combo = combo[i+2:]
+	}
+}
+
+// comboScancodes returns the scancodes pressing the keys of combo in order
+// and releasing them in reverse order. A key is only pressed once, so
+// "Shift+!" presses Shift once.
+func comboScancodes(layout KeyboardLayout, combo string) ([]int32, error) {
+	var codes []KeyCode
+	for _, name := range splitCombo(combo) {
+		keyCodes, err := parseKey(layout, strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+	next:
+		for _, code := range keyCodes {
+			for _, c := range codes {
+				if c == code {
+					continue next
+				}
+			}
+			codes = append(codes, code)
+		}
+	}
+
+	var scancodes []int32
+	for _, code := range codes {
+		scancodes = append(scancodes, code.makeCodes()...)
+	}
+	for i := len(codes) - 1; i >= 0; i-- {
+		scancodes = append(scancodes, codes[i].breakCodes()...)
+	}
+	return scancodes, nil
+}
+
+// Press presses each key combination in turn, stopping when ctx is done. A
+// combination is a list of key names joined by '+', such as "Ctrl+Alt+F2",
+// "Enter" or "Ctrl++"; its keys are pressed in order and released in
+// reverse order. A character that needs Shift or AltGr in the layout, such
+// as the '+' of "Ctrl++" on a US keyboard, is pressed with that modifier.
+func (k *Keyboard) Press(ctx context.Context, keys ...string) error {
+	layout := k.Layout
+	if layout == nil {
+		layout = LayoutUS
+	}
+
+	for _, combo := range keys {
+		scancodes, err := comboScancodes(layout, combo)
+		if err != nil {
+			return err
+		}
+		if err := k.put(ctx, scancodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package vboxapi
+
+// KeyCode is a PS/2 scan code set 1 make code. Extended keys, which are
+// prefixed with 0xE0, have KeyExtended set.
+type KeyCode uint16
+
+// KeyExtended marks a KeyCode as an extended key.
+const KeyExtended KeyCode = 0xE000
+
+// Make codes of the keys that are not characters.
+const (
+	keyEsc        KeyCode = 0x01
+	keyBackspace  KeyCode = 0x0E
+	keyTab        KeyCode = 0x0F
+	keyEnter      KeyCode = 0x1C
+	keyLeftCtrl   KeyCode = 0x1D
+	keyLeftShift  KeyCode = 0x2A
+	keyRightShift KeyCode = 0x36
+	keyLeftAlt    KeyCode = 0x38
+	keySpace      KeyCode = 0x39
+	keyCapsLock   KeyCode = 0x3A
+	keyF1         KeyCode = 0x3B
+	keyNumLock    KeyCode = 0x45
+	keyScrollLock KeyCode = 0x46
+	keyF11        KeyCode = 0x57
+	keyF12        KeyCode = 0x58
+	keyRightCtrl  KeyCode = KeyExtended | 0x1D
+	keyRightAlt   KeyCode = KeyExtended | 0x38
+	keyHome       KeyCode = KeyExtended | 0x47
+	keyUp         KeyCode = KeyExtended | 0x48
+	keyPageUp     KeyCode = KeyExtended | 0x49
+	keyLeft       KeyCode = KeyExtended | 0x4B
+	keyRight      KeyCode = KeyExtended | 0x4D
+	keyEnd        KeyCode = KeyExtended | 0x4F
+	keyDown       KeyCode = KeyExtended | 0x50
+	keyPageDown   KeyCode = KeyExtended | 0x51
+	keyInsert     KeyCode = KeyExtended | 0x52
+	keyDelete     KeyCode = KeyExtended | 0x53
+	keyLeftSuper  KeyCode = KeyExtended | 0x5B
+	keyRightSuper KeyCode = KeyExtended | 0x5C
+	keyMenu       KeyCode = KeyExtended | 0x5D
+)
+
+// makeCodes returns the scan codes sent when the key is pressed.
+func (k KeyCode) makeCodes() []int32 {
+	if k&KeyExtended != 0 {
+		return []int32{0xE0, int32(k & 0xFF)}
+	}
+	return []int32{int32(k)}
+}
+
+// breakCodes returns the scan codes sent when the key is released.
+func (k KeyCode) breakCodes() []int32 {
+	if k&KeyExtended != 0 {
+		return []int32{0xE0, int32(k&0xFF) | 0x80}
+	}
+	return []int32{int32(k) | 0x80}
+}
+
+// KeyStroke is the key and modifiers that produce a character.
+type KeyStroke struct {
+	Code  KeyCode
+	Shift bool
+	AltGr bool
+	// Dead keys only produce their character when followed by a space.
+	Dead bool
+}
+
+// KeyboardLayout maps the characters typed by Keyboard.Type to the keys
+// that produce them.
+type KeyboardLayout map[rune]KeyStroke
+
+// addRow adds the characters of a row of keys, produced by consecutive
+// make codes starting at first, to l.
+func (l KeyboardLayout) addRow(first KeyCode, plain, shifted string) {
+	code := first
+	for _, r := range plain {
+		l[r] = KeyStroke{Code: code}
+		code++
+	}
+	code = first
+	for _, r := range shifted {
+		l[r] = KeyStroke{Code: code, Shift: true}
+		code++
+	}
+}
+
+func newKeyboardLayout() KeyboardLayout {
+	return KeyboardLayout{
+		' ':  {Code: keySpace},
+		'\n': {Code: keyEnter},
+		'\t': {Code: keyTab},
+	}
+}
+
+// LayoutUS is the US English QWERTY layout.
+var LayoutUS = func() KeyboardLayout {
+	l := newKeyboardLayout()
+	l['`'] = KeyStroke{Code: 0x29}
+	l['~'] = KeyStroke{Code: 0x29, Shift: true}
+	l.addRow(0x02, "1234567890-=", "!@#$%^&*()_+")
+	l.addRow(0x10, "qwertyuiop[]", "QWERTYUIOP{}")
+	l['\\'] = KeyStroke{Code: 0x2B}
+	l['|'] = KeyStroke{Code: 0x2B, Shift: true}
+	l.addRow(0x1E, "asdfghjkl;'", "ASDFGHJKL:\"")
+	l.addRow(0x2C, "zxcvbnm,./", "ZXCVBNM<>?")
+	return l
+}()
+
+// LayoutDE is the German QWERTZ layout.
+var LayoutDE = func() KeyboardLayout {
+	l := newKeyboardLayout()
+	l['^'] = KeyStroke{Code: 0x29, Dead: true}
+	l['°'] = KeyStroke{Code: 0x29, Shift: true}
+	l.addRow(0x02, "1234567890ß", "!\"§$%&/()=?")
+	l['´'] = KeyStroke{Code: 0x0D, Dead: true}
+	l['`'] = KeyStroke{Code: 0x0D, Shift: true, Dead: true}
+	l.addRow(0x10, "qwertzuiopü+", "QWERTZUIOPÜ*")
+	l['#'] = KeyStroke{Code: 0x2B}
+	l['\''] = KeyStroke{Code: 0x2B, Shift: true}
+	l.addRow(0x1E, "asdfghjklöä", "ASDFGHJKLÖÄ")
+	l['<'] = KeyStroke{Code: 0x56}
+	l['>'] = KeyStroke{Code: 0x56, Shift: true}
+	l.addRow(0x2C, "yxcvbnm,.-", "YXCVBNM;:_")
+
+	for r, code := range map[rune]KeyCode{
+		'²': 0x03, '³': 0x04, '{': 0x08, '[': 0x09, ']': 0x0A, '}': 0x0B,
+		'\\': 0x0C, '@': 0x10, '€': 0x12, '~': 0x1B, '|': 0x56, 'µ': 0x32,
+	} {
+		l[r] = KeyStroke{Code: code, AltGr: true}
+	}
+	return l
+}()
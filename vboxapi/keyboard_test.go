@@ -0,0 +1,95 @@
+package vboxapi
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTypeScancodes(t *testing.T) {
+	tests := []struct {
+		layout  KeyboardLayout
+		text    string
+		want    [][]int32
+		wantErr bool
+	}{
+		{LayoutUS, "a", [][]int32{{0x1E, 0x9E}}, false},
+		{LayoutUS, "A!", [][]int32{{0x2A, 0x1E, 0x9E, 0xAA}, {0x2A, 0x02, 0x82, 0xAA}}, false},
+		{LayoutUS, "\n", [][]int32{{0x1C, 0x9C}}, false},
+		{LayoutDE, "@", [][]int32{{0xE0, 0x38, 0x10, 0x90, 0xE0, 0xB8}}, false},
+		{LayoutDE, "^", [][]int32{{0x29, 0xA9, 0x39, 0xB9}}, false},
+		{LayoutDE, "`", [][]int32{{0x2A, 0x0D, 0x8D, 0xAA, 0x39, 0xB9}}, false},
+		{LayoutUS, "", [][]int32{}, false},
+		{LayoutUS, "ä", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := typeScancodes(tt.layout, tt.text)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("typeScancodes(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("typeScancodes(%q) = %#x, want %#x", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplitCombo(t *testing.T) {
+	tests := []struct {
+		combo string
+		want  []string
+	}{
+		{"Enter", []string{"Enter"}},
+		{"Ctrl+Alt+F2", []string{"Ctrl", "Alt", "F2"}},
+		{"+", []string{"+"}},
+		{"Ctrl++", []string{"Ctrl", "+"}},
+		{"Ctrl+Shift++", []string{"Ctrl", "Shift", "+"}},
+		{"Ctrl+", []string{"Ctrl", ""}},
+		{"", []string{""}},
+	}
+
+	for _, tt := range tests {
+		if got := splitCombo(tt.combo); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCombo(%q) = %q, want %q", tt.combo, got, tt.want)
+		}
+	}
+}
+
+func TestComboScancodes(t *testing.T) {
+	tests := []struct {
+		layout  KeyboardLayout
+		combo   string
+		want    []int32
+		wantErr bool
+	}{
+		{LayoutUS, "Enter", []int32{0x1C, 0x9C}, false},
+		{LayoutUS, "Ctrl+Alt+F2", []int32{0x1D, 0x38, 0x3C, 0xBC, 0xB8, 0x9D}, false},
+		{LayoutUS, "Ctrl+a", []int32{0x1D, 0x1E, 0x9E, 0x9D}, false},
+		{LayoutUS, "Ctrl++", []int32{0x1D, 0x2A, 0x0D, 0x8D, 0xAA, 0x9D}, false},
+		{LayoutUS, "Shift+!", []int32{0x2A, 0x02, 0x82, 0xAA}, false},
+		{LayoutDE, "Ctrl+@", []int32{0x1D, 0xE0, 0x38, 0x10, 0x90, 0xE0, 0xB8, 0x9D}, false},
+		{LayoutUS, "Ctrl+Nope", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := comboScancodes(tt.layout, tt.combo)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("comboScancodes(%q) error = %v, wantErr %v", tt.combo, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("comboScancodes(%q) = %#x, want %#x", tt.combo, got, tt.want)
+		}
+	}
+}
+
+func TestKeyboardPutPartial(t *testing.T) {
+	f := newFakeVBox(t)
+	f.respond("IKeyboard_putScancodes", "<returnval>1</returnval>")
+	k := &Keyboard{virtualbox: f.VirtualBox, managedObjectId: "keyboard"}
+
+	if err := k.put(context.Background(), []int32{0x1E, 0x9E}); err == nil {
+		t.Error("put() = nil, want an error when only part of the scancodes is stored")
+	}
+}
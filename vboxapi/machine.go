@@ -1,7 +1,10 @@
 package vboxapi
 
 import (
+	"encoding/base64"
 	"errors"
+	"image"
+	"time"
 
 	"github.com/blacktop/go-vboxapi/vboxweb"
 )
@@ -24,6 +27,84 @@ func (m *Machine) GetChipsetType() (*vboxweb.ChipsetType, error) {
 	return response.Returnval, nil
 }
 
+func (m *Machine) GetState() (*vboxweb.MachineState, error) {
+	request := vboxweb.IMachinegetState{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMachinegetState(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GetLastStateChange returns the time the machine last changed state.
+func (m *Machine) GetLastStateChange() (time.Time, error) {
+	request := vboxweb.IMachinegetLastStateChange{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMachinegetLastStateChange(&request)
+	if err != nil {
+		return time.Time{}, err // TODO: Wrap the error
+	}
+
+	return time.Unix(0, response.Returnval*int64(time.Millisecond)), nil
+}
+
+func (m *Machine) GetVRAMSize() (uint32, error) {
+	request := vboxweb.IMachinegetVRAMSize{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMachinegetVRAMSize(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// SetVRAMSize sets the video memory of the VM in MiB. The VM must be
+// powered off.
+func (m *Machine) SetVRAMSize(size uint32) error {
+	return m.withSessionMachine(func(sm *Machine) error {
+		request := vboxweb.IMachinesetVRAMSize{This: sm.managedObjectId, VRAMSize: size}
+
+		_, err := m.virtualbox.IMachinesetVRAMSize(&request)
+		return err // TODO: Wrap the error
+	})
+}
+
+func (m *Machine) GetMonitorCount() (uint32, error) {
+	request := vboxweb.IMachinegetMonitorCount{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMachinegetMonitorCount(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// SetMonitorCount sets the number of virtual monitors of the VM. The VM
+// must be powered off.
+func (m *Machine) SetMonitorCount(count uint32) error {
+	return m.withSessionMachine(func(sm *Machine) error {
+		request := vboxweb.IMachinesetMonitorCount{This: sm.managedObjectId, MonitorCount: count}
+
+		_, err := m.virtualbox.IMachinesetMonitorCount(&request)
+		return err // TODO: Wrap the error
+	})
+}
+
+func (m *Machine) GetBandwidthControl() (*BandwidthControl, error) {
+	request := vboxweb.IMachinegetBandwidthControl{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMachinegetBandwidthControl(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &BandwidthControl{virtualbox: m.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
 func (m *Machine) GetMediumAttachments() ([]*vboxweb.IMediumAttachment, error) {
 	request := vboxweb.IMachinegetMediumAttachments{This: m.managedObjectId}
 
@@ -47,6 +128,62 @@ func (m *Machine) GetMediumAttachmentsOfController(cName string) ([]*vboxweb.IMe
 	return response.Returnval, nil
 }
 
+// Attachments returns all medium attachments of the machine. Release them
+// when done to free the references to their media.
+func (m *Machine) Attachments() ([]*MediumAttachment, error) {
+	mas, err := m.GetMediumAttachments()
+	if err != nil {
+		return nil, err
+	}
+
+	return m.newMediumAttachments(mas)
+}
+
+// AttachmentsOfController returns the medium attachments of the storage
+// controller named cName.
+func (m *Machine) AttachmentsOfController(cName string) ([]*MediumAttachment, error) {
+	mas, err := m.GetMediumAttachmentsOfController(cName)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.newMediumAttachments(mas)
+}
+
+// AttachmentsOfType returns the medium attachments of devices of the given
+// type.
+func (m *Machine) AttachmentsOfType(deviceType vboxweb.DeviceType) ([]*MediumAttachment, error) {
+	mas, err := m.GetMediumAttachments()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []*vboxweb.IMediumAttachment
+	for _, ma := range mas {
+		if ma.Type_ != nil && *ma.Type_ == deviceType {
+			filtered = append(filtered, ma)
+		}
+	}
+
+	return m.newMediumAttachments(filtered)
+}
+
+func (m *Machine) newMediumAttachments(mas []*vboxweb.IMediumAttachment) ([]*MediumAttachment, error) {
+	attachments := make([]*MediumAttachment, len(mas))
+	for i, ma := range mas {
+		a, err := newMediumAttachment(m.virtualbox, ma)
+		if err != nil {
+			for _, prev := range attachments[:i] {
+				prev.Release()
+			}
+			return nil, err
+		}
+		attachments[i] = a
+	}
+
+	return attachments, nil
+}
+
 func (m *Machine) GetNetworkAdapter(slot uint32) (*NetworkAdapter, error) {
 	request := vboxweb.IMachinegetNetworkAdapter{This: m.managedObjectId, Slot: slot}
 
@@ -58,6 +195,75 @@ func (m *Machine) GetNetworkAdapter(slot uint32) (*NetworkAdapter, error) {
 	return &NetworkAdapter{m.virtualbox, response.Returnval}, nil
 }
 
+// NetworkAdapters reads the configuration of every network adapter slot
+// supported by the machine's chipset.
+func (m *Machine) NetworkAdapters() ([]*NetworkAdapterConfig, error) {
+	chipset, err := m.GetChipsetType()
+	if err != nil {
+		return nil, err
+	}
+
+	sp, err := m.virtualbox.GetSystemProperties()
+	if err != nil {
+		return nil, err
+	}
+	defer sp.Release()
+
+	count, err := sp.GetMaxNetworkAdapters(chipset)
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]*NetworkAdapterConfig, count)
+	for slot := uint32(0); slot < count; slot++ {
+		na, err := m.GetNetworkAdapter(slot)
+		if err != nil {
+			return nil, err
+		}
+
+		c, err := na.Config()
+		na.Release()
+		if err != nil {
+			return nil, err
+		}
+		c.machine = m
+		configs[slot] = c
+	}
+
+	return configs, nil
+}
+
+// ApplyNetworkAdapters writes each configuration to its slot under a session
+// lock and saves the settings.
+func (m *Machine) ApplyNetworkAdapters(configs ...*NetworkAdapterConfig) error {
+	return m.withSessionMachine(func(sm *Machine) error {
+		bc, err := sm.GetBandwidthControl()
+		if err != nil {
+			return err
+		}
+		defer bc.Release()
+
+		for _, c := range configs {
+			na, err := sm.GetNetworkAdapter(c.Slot)
+			if err != nil {
+				return err
+			}
+			defer na.Release()
+
+			cur, err := na.Config()
+			if err != nil {
+				return err
+			}
+
+			if err := na.apply(c, cur, bc); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
 func (m *Machine) GetSettingsFilePath() (string, error) {
 	request := vboxweb.IMachinegetSettingsFilePath{This: m.managedObjectId}
 
@@ -130,54 +336,166 @@ func (m *Machine) GetStorageController(name string) (*StorageController, error)
 	return nil, errors.New("storage controller not found")
 }
 
-func (m *Machine) AttachDevice(medium *Medium) error {
-	session, err := m.virtualbox.GetSession()
-	if err != nil {
-		return err
+// AddStorageController adds a storage controller named name on the given bus.
+// If controllerType is empty, VirtualBox picks the default type for the bus.
+func (m *Machine) AddStorageController(name string, bus vboxweb.StorageBus, controllerType vboxweb.StorageControllerType) error {
+	if name == "" {
+		return errors.New("storage controller name not specified")
 	}
-	// defer session.Release()
 
-	if err := m.Lock(session, vboxweb.LockTypeShared); err != nil {
-		return err
-	}
-	defer m.Unlock(session)
+	return m.withSessionMachine(func(sm *Machine) error {
+		request := vboxweb.IMachineaddStorageController{
+			This:           sm.managedObjectId,
+			Name:           name,
+			ConnectionType: &bus,
+		}
 
-	sm, err := session.GetMachine()
-	if err != nil {
-		return err
+		response, err := m.virtualbox.IMachineaddStorageController(&request)
+		if err != nil {
+			return err // TODO: Wrap the error
+		}
+
+		sc := &StorageController{virtualbox: m.virtualbox, managedObjectId: response.Returnval, Name: name}
+		defer sc.Release()
+
+		if controllerType != "" {
+			return sc.SetControllerType(controllerType)
+		}
+		return nil
+	})
+}
+
+// RemoveStorageController removes the storage controller named name. All
+// devices must be detached from it first.
+func (m *Machine) RemoveStorageController(name string) error {
+	if name == "" {
+		return errors.New("storage controller name not specified")
 	}
-	defer sm.Release()
 
-	if m.virtualbox.controllerName == "" {
+	return m.withSessionMachine(func(sm *Machine) error {
+		request := vboxweb.IMachineremoveStorageController{This: sm.managedObjectId, Name: name}
+
+		_, err := m.virtualbox.IMachineremoveStorageController(&request)
+		return err // TODO: Wrap the error
+	})
+}
+
+// AttachOptions describes where and how a medium is attached to a machine.
+type AttachOptions struct {
+	// Controller is the name of the storage controller. It defaults to the
+	// controller name the VirtualBox client was created with.
+	Controller string
+	// Port is the controller port. If nil, the first port whose Device slot is
+	// free is used.
+	Port *int32
+	// Device is the device slot on the port.
+	Device int32
+	// Type is the device type. It defaults to the device type of the medium.
+	Type vboxweb.DeviceType
+
+	NonRotational bool
+	Discard       bool
+	HotPluggable  bool
+	Passthrough   bool
+}
+
+// AttachDevice attaches medium to the machine as described by opts.
+func (m *Machine) AttachDevice(medium *Medium, opts AttachOptions) error {
+	if opts.Controller == "" {
+		opts.Controller = m.virtualbox.controllerName
+	}
+	if opts.Controller == "" {
 		return errors.New("missing controllerName")
 	}
-
-	sc, err := sm.GetStorageController(m.virtualbox.controllerName)
-	if err != nil {
-		return err
+	if opts.Type == "" {
+		opts.Type = medium.DeviceType
 	}
 
-	pn, err := sc.GetNextAvailablePort(m)
-	if err != nil {
-		return err
+	return m.withSessionMachine(func(sm *Machine) error {
+		sc, err := sm.GetStorageController(opts.Controller)
+		if err != nil {
+			return err
+		}
+		defer sc.Release()
+
+		var port int32
+		if opts.Port != nil {
+			port = *opts.Port
+		} else {
+			port, err = sc.GetNextAvailablePortForDevice(m, opts.Device)
+			if err != nil {
+				return err
+			}
+		}
+
+		request := vboxweb.IMachineattachDevice{
+			This:           sm.managedObjectId,
+			Name:           sc.Name,
+			ControllerPort: port,
+			Device:         opts.Device,
+			Type_:          &opts.Type,
+			Medium:         medium.managedObjectId,
+		}
+
+		if _, err := m.virtualbox.IMachineattachDevice(&request); err != nil {
+			return err // TODO: Wrap the error
+		}
+
+		return sm.setDeviceOptions(sc.Name, port, opts)
+	})
+}
+
+func (m *Machine) setDeviceOptions(controller string, port int32, opts AttachOptions) error {
+	if opts.NonRotational {
+		request := vboxweb.IMachinenonRotationalDevice{
+			This:           m.managedObjectId,
+			Name:           controller,
+			ControllerPort: port,
+			Device:         opts.Device,
+			NonRotational:  true,
+		}
+		if _, err := m.virtualbox.IMachinenonRotationalDevice(&request); err != nil {
+			return err // TODO: Wrap the error
+		}
 	}
 
-	request := vboxweb.IMachineattachDevice{
-		This:           sm.managedObjectId,
-		Name:           sc.Name,
-		ControllerPort: pn,
-		Device:         0,
-		Type_:          &medium.DeviceType,
-		Medium:         medium.managedObjectId,
+	if opts.Discard {
+		request := vboxweb.IMachinesetAutoDiscardForDevice{
+			This:           m.managedObjectId,
+			Name:           controller,
+			ControllerPort: port,
+			Device:         opts.Device,
+			Discard:        true,
+		}
+		if _, err := m.virtualbox.IMachinesetAutoDiscardForDevice(&request); err != nil {
+			return err // TODO: Wrap the error
+		}
 	}
 
-	_, err = m.virtualbox.IMachineattachDevice(&request)
-	if err != nil {
-		return err
+	if opts.HotPluggable {
+		request := vboxweb.IMachinesetHotPluggableForDevice{
+			This:           m.managedObjectId,
+			Name:           controller,
+			ControllerPort: port,
+			Device:         opts.Device,
+			HotPluggable:   true,
+		}
+		if _, err := m.virtualbox.IMachinesetHotPluggableForDevice(&request); err != nil {
+			return err // TODO: Wrap the error
+		}
 	}
 
-	if err := sm.SaveSettings(); err != nil {
-		return err
+	if opts.Passthrough {
+		request := vboxweb.IMachinepassthroughDevice{
+			This:           m.managedObjectId,
+			Name:           controller,
+			ControllerPort: port,
+			Device:         opts.Device,
+			Passthrough:    true,
+		}
+		if _, err := m.virtualbox.IMachinepassthroughDevice(&request); err != nil {
+			return err // TODO: Wrap the error
+		}
 	}
 
 	return nil
@@ -209,6 +527,10 @@ func (m *Machine) DetachDevice(medium *Medium) error {
 
 	var request *vboxweb.IMachinedetachDevice
 	for _, ma := range mediumAttachments {
+		if ma.Medium == "" {
+			// Empty drive, nothing to compare against.
+			continue
+		}
 		am := &Medium{virtualbox: m.virtualbox, managedObjectId: ma.Medium}
 		defer am.Release()
 		amID, err := am.GetID()
@@ -223,7 +545,7 @@ func (m *Machine) DetachDevice(medium *Medium) error {
 			This:           sm.managedObjectId,
 			Name:           ma.Controller,
 			ControllerPort: ma.Port,
-			Device:         0,
+			Device:         ma.Device,
 		}
 	}
 	if request == nil {
@@ -242,6 +564,90 @@ func (m *Machine) DetachDevice(medium *Medium) error {
 	return nil
 }
 
+// MountMedium mounts medium into the removable device (DVD or floppy drive)
+// at the given controller, port and device. On a running VM the medium is
+// changed immediately. A nil medium leaves the drive empty.
+func (m *Machine) MountMedium(controller string, port, device int32, medium *Medium, force bool) error {
+	return m.withSessionMachine(func(sm *Machine) error {
+		request := vboxweb.IMachinemountMedium{
+			This:           sm.managedObjectId,
+			Name:           controller,
+			ControllerPort: port,
+			Device:         device,
+			Force:          force,
+		}
+		if medium != nil {
+			request.Medium = medium.managedObjectId
+		}
+
+		_, err := m.virtualbox.IMachinemountMedium(&request)
+		return err // TODO: Wrap the error
+	})
+}
+
+// UnmountMedium ejects the medium from the removable device at the given
+// controller, port and device, leaving the drive attached but empty.
+func (m *Machine) UnmountMedium(controller string, port, device int32, force bool) error {
+	return m.withSessionMachine(func(sm *Machine) error {
+		request := vboxweb.IMachineunmountMedium{
+			This:           sm.managedObjectId,
+			Name:           controller,
+			ControllerPort: port,
+			Device:         device,
+			Force:          force,
+		}
+
+		_, err := m.virtualbox.IMachineunmountMedium(&request)
+		return err // TODO: Wrap the error
+	})
+}
+
+// AttachDeviceWithoutMedium attaches an empty device of the given type,
+// typically a DVD or floppy drive, at the given controller, port and device.
+func (m *Machine) AttachDeviceWithoutMedium(controller string, port, device int32, deviceType vboxweb.DeviceType) error {
+	return m.withSessionMachine(func(sm *Machine) error {
+		request := vboxweb.IMachineattachDeviceWithoutMedium{
+			This:           sm.managedObjectId,
+			Name:           controller,
+			ControllerPort: port,
+			Device:         device,
+			Type_:          &deviceType,
+		}
+
+		_, err := m.virtualbox.IMachineattachDeviceWithoutMedium(&request)
+		return err // TODO: Wrap the error
+	})
+}
+
+// withSessionMachine takes a shared lock on the machine and calls fn with the
+// mutable session machine, saving its settings if fn succeeds. A shared lock
+// on a running VM yields the machine of its console session, so changes made
+// by fn are applied to the VM while it runs.
+func (m *Machine) withSessionMachine(fn func(sm *Machine) error) error {
+	session, err := m.virtualbox.GetSession()
+	if err != nil {
+		return err
+	}
+	// defer session.Release()
+
+	if err := m.Lock(session, vboxweb.LockTypeShared); err != nil {
+		return err
+	}
+	defer m.Unlock(session)
+
+	sm, err := session.GetMachine()
+	if err != nil {
+		return err
+	}
+	defer sm.Release()
+
+	if err := fn(sm); err != nil {
+		return err
+	}
+
+	return sm.SaveSettings()
+}
+
 func (m *Machine) Unlock(session *Session) error {
 	if err := session.UnlockMachine(); err != nil {
 		return err
@@ -280,6 +686,72 @@ func (m *Machine) GetName() (string, error) {
 	return response.Returnval, nil
 }
 
+// SavedScreenshot reads the screenshot stored with the saved state of a VM
+// in the Saved state.
+func (m *Machine) SavedScreenshot(screenID uint32) (image.Image, error) {
+	if err := m.requireSaved(); err != nil {
+		return nil, err
+	}
+
+	info := vboxweb.IMachinequerySavedScreenshotInfo{This: m.managedObjectId, ScreenId: screenID}
+	infoResponse, err := m.virtualbox.IMachinequerySavedScreenshotInfo(&info)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	format := vboxweb.BitmapFormatBGR0
+	for _, f := range infoResponse.Returnval {
+		if f != nil && *f == vboxweb.BitmapFormatPNG {
+			format = vboxweb.BitmapFormatPNG
+			break
+		}
+	}
+
+	request := vboxweb.IMachinereadSavedScreenshotToArray{This: m.managedObjectId, ScreenId: screenID, BitmapFormat: &format}
+	response, err := m.virtualbox.IMachinereadSavedScreenshotToArray(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	data, err := base64.StdEncoding.DecodeString(response.Returnval)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBitmap(format, response.Width, response.Height, data)
+}
+
+// SavedThumbnail reads the thumbnail stored with the saved state of a VM
+// in the Saved state.
+func (m *Machine) SavedThumbnail(screenID uint32) (image.Image, error) {
+	if err := m.requireSaved(); err != nil {
+		return nil, err
+	}
+
+	format := vboxweb.BitmapFormatBGR0
+	request := vboxweb.IMachinereadSavedThumbnailToArray{This: m.managedObjectId, ScreenId: screenID, BitmapFormat: &format}
+	response, err := m.virtualbox.IMachinereadSavedThumbnailToArray(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	data, err := base64.StdEncoding.DecodeString(response.Returnval)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBitmap(format, response.Width, response.Height, data)
+}
+
+func (m *Machine) requireSaved() error {
+	state, err := m.GetState()
+	if err != nil {
+		return err
+	}
+	if state == nil || *state != vboxweb.MachineStateSaved {
+		return errors.New("machine is not in the Saved state")
+	}
+	return nil
+}
+
 func (m *Machine) Release() error {
 	return m.virtualbox.Release(m.managedObjectId)
 }
@@ -19,6 +19,8 @@ type Medium struct {
 	ID              string
 	MachineIDs      []string
 	SnapshotIDs     []string
+	State           vboxweb.MediumState
+	LastAccessError string
 }
 
 func (m *Medium) CreateBaseStorage(logicalSize int64, variant []*vboxweb.MediumVariant) (*Progress, error) {
@@ -133,6 +135,64 @@ func (m *Medium) GetState() (*vboxweb.MediumState, error) {
 	return response.Returnval, nil
 }
 
+// RefreshState re-reads the accessibility of the medium from its storage.
+func (m *Medium) RefreshState() (*vboxweb.MediumState, error) {
+	request := vboxweb.IMediumrefreshState{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMediumrefreshState(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	if response.Returnval != nil {
+		m.State = *response.Returnval
+	}
+	return response.Returnval, nil
+}
+
+func (m *Medium) GetLastAccessError() (string, error) {
+	request := vboxweb.IMediumgetLastAccessError{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMediumgetLastAccessError(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// Accessible reports whether the medium was accessible when its state was
+// last read by Get or RefreshState.
+func (m *Medium) Accessible() bool {
+	return m.State != "" && m.State != vboxweb.MediumStateInaccessible
+}
+
+// LockRead locks the medium for reading so that it cannot be written to
+// until the returned Token is closed.
+func (m *Medium) LockRead() (*Token, error) {
+	request := vboxweb.IMediumlockRead{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMediumlockRead(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Token{virtualbox: m.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// LockWrite locks the medium for exclusive writing so that no other access
+// is possible until the returned Token is closed.
+func (m *Medium) LockWrite() (*Token, error) {
+	request := vboxweb.IMediumlockWrite{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMediumlockWrite(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Token{virtualbox: m.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
 func (m *Medium) GetFormat() (string, error) {
 	request := vboxweb.IMediumgetFormat{This: m.managedObjectId}
 
@@ -316,6 +376,17 @@ func (m *Medium) Get() (*Medium, error) {
 		return nil, err
 	}
 
+	st, err := m.GetState()
+	if err != nil {
+		return nil, err
+	}
+	m.State = *st
+
+	m.LastAccessError, err = m.GetLastAccessError()
+	if err != nil {
+		return nil, err
+	}
+
 	return m, nil
 
 }
@@ -2,12 +2,71 @@ package vboxapi
 
 import "github.com/blacktop/go-vboxapi/vboxweb"
 
+// MediumAttachment describes a device attached to a storage controller of a
+// machine, together with the medium inserted in it.
 type MediumAttachment struct {
-	*vboxweb.IMediumAttachment
-	virtualbox      *VirtualBox
-	managedObjectId string
+	virtualbox *VirtualBox
+
+	// Medium is the attached medium, or nil for an empty drive.
+	Medium         *Medium
+	Controller     string
+	Port           int32
+	Device         int32
+	Type           vboxweb.DeviceType
+	Passthrough    bool
+	TemporaryEject bool
+	IsEjected      bool
+	NonRotational  bool
+	Discard        bool
+	HotPluggable   bool
+	// BandwidthGroup is the name of the bandwidth group, if any.
+	BandwidthGroup string
+}
+
+func newMediumAttachment(vb *VirtualBox, ma *vboxweb.IMediumAttachment) (*MediumAttachment, error) {
+	a := &MediumAttachment{
+		virtualbox:     vb,
+		Controller:     ma.Controller,
+		Port:           ma.Port,
+		Device:         ma.Device,
+		Passthrough:    ma.Passthrough,
+		TemporaryEject: ma.TemporaryEject,
+		IsEjected:      ma.IsEjected,
+		NonRotational:  ma.NonRotational,
+		Discard:        ma.Discard,
+		HotPluggable:   ma.HotPluggable,
+	}
+	if ma.Type_ != nil {
+		a.Type = *ma.Type_
+	}
+
+	if ma.BandwidthGroup != "" {
+		bg := &BandwidthGroup{virtualbox: vb, managedObjectId: ma.BandwidthGroup}
+		defer bg.Release()
+
+		name, err := bg.GetName()
+		if err != nil {
+			return nil, err
+		}
+		a.BandwidthGroup = name
+	}
+
+	if ma.Medium != "" {
+		medium := &Medium{virtualbox: vb, managedObjectId: ma.Medium}
+		if _, err := medium.Get(); err != nil {
+			medium.Release()
+			return nil, err
+		}
+		a.Medium = medium
+	}
+
+	return a, nil
 }
 
-func (m *MediumAttachment) GetMedium() (*Medium, error) {
-	return &Medium{virtualbox: m.virtualbox, managedObjectId: m.Medium}, nil
+// Release releases the managed object reference of the attached medium.
+func (m *MediumAttachment) Release() error {
+	if m.Medium == nil {
+		return nil
+	}
+	return m.Medium.Release()
 }
@@ -0,0 +1,253 @@
+package vboxapi
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// MouseButtonState is a set of mouse buttons held down.
+type MouseButtonState int32
+
+// Mouse buttons, matching VirtualBox's MouseButtonState values.
+const (
+	MouseLeftButton   MouseButtonState = 0x01
+	MouseRightButton  MouseButtonState = 0x02
+	MouseMiddleButton MouseButtonState = 0x04
+	MouseWheelUp      MouseButtonState = 0x08
+	MouseWheelDown    MouseButtonState = 0x10
+	MouseXButton1     MouseButtonState = 0x20
+	MouseXButton2     MouseButtonState = 0x40
+)
+
+// Has reports whether all buttons in b are set in s.
+func (s MouseButtonState) Has(b MouseButtonState) bool {
+	return s&b == b
+}
+
+// mouseClickDuration is how long Click holds the button down.
+const mouseClickDuration = 50 * time.Millisecond
+
+// Mouse is the virtual pointing device of a running VM.
+type Mouse struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// TouchContact is a single contact of a multi-touch event.
+type TouchContact struct {
+	X, Y      uint16
+	ID        uint8
+	InContact bool
+	InRange   bool
+}
+
+// pack encodes the contact in the 64-bit form expected by
+// IMouse::putEventMultiTouch.
+func (c TouchContact) pack() int64 {
+	var flags int64
+	if c.InContact {
+		flags |= 0x01
+	}
+	if c.InRange {
+		flags |= 0x02
+	}
+	return int64(c.X) | int64(c.Y)<<16 | int64(c.ID)<<32 | flags<<40
+}
+
+// Mouse returns the mouse of the console's VM.
+func (c *Console) Mouse() (*Mouse, error) {
+	request := vboxweb.IConsolegetMouse{This: c.managedObjectID}
+
+	response, err := c.virtualbox.IConsolegetMouse(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Mouse{virtualbox: c.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+func (m *Mouse) GetAbsoluteSupported() (bool, error) {
+	request := vboxweb.IMousegetAbsoluteSupported{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMousegetAbsoluteSupported(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (m *Mouse) GetRelativeSupported() (bool, error) {
+	request := vboxweb.IMousegetRelativeSupported{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMousegetRelativeSupported(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (m *Mouse) GetMultiTouchSupported() (bool, error) {
+	request := vboxweb.IMousegetMultiTouchSupported{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMousegetMultiTouchSupported(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (m *Mouse) GetNeedsHostCursor() (bool, error) {
+	request := vboxweb.IMousegetNeedsHostCursor{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMousegetNeedsHostCursor(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// PutMouseEvent moves the pointer by dx and dy pixels, scrolls by dz
+// (vertical) and dw (horizontal) wheel steps and sets the buttons held.
+func (m *Mouse) PutMouseEvent(dx, dy, dz, dw int32, buttons MouseButtonState) error {
+	request := vboxweb.IMouseputMouseEvent{
+		This:        m.managedObjectId,
+		Dx:          dx,
+		Dy:          dy,
+		Dz:          dz,
+		Dw:          dw,
+		ButtonState: int32(buttons),
+	}
+
+	_, err := m.virtualbox.IMouseputMouseEvent(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// PutMouseEventAbsolute moves the pointer to x and y, in pixels starting
+// from 1, scrolls by dz and dw wheel steps and sets the buttons held.
+func (m *Mouse) PutMouseEventAbsolute(x, y, dz, dw int32, buttons MouseButtonState) error {
+	request := vboxweb.IMouseputMouseEventAbsolute{
+		This:        m.managedObjectId,
+		X:           x,
+		Y:           y,
+		Dz:          dz,
+		Dw:          dw,
+		ButtonState: int32(buttons),
+	}
+
+	_, err := m.virtualbox.IMouseputMouseEventAbsolute(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// PutEventMultiTouch sends the state of the touch contacts. scanTime is a
+// timestamp in milliseconds.
+func (m *Mouse) PutEventMultiTouch(contacts []TouchContact, scanTime uint32) error {
+	packed := make([]int64, len(contacts))
+	for i, c := range contacts {
+		packed[i] = c.pack()
+	}
+	request := vboxweb.IMouseputEventMultiTouch{
+		This:     m.managedObjectId,
+		Count:    int32(len(contacts)),
+		Contacts: packed,
+		ScanTime: scanTime,
+	}
+
+	_, err := m.virtualbox.IMouseputEventMultiTouch(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// PutEventMultiTouchString is like PutEventMultiTouch but passes the packed
+// contacts as a comma-separated string.
+func (m *Mouse) PutEventMultiTouchString(contacts []TouchContact, scanTime uint32) error {
+	packed := make([]string, len(contacts))
+	for i, c := range contacts {
+		packed[i] = strconv.FormatInt(c.pack(), 10)
+	}
+	request := vboxweb.IMouseputEventMultiTouchString{
+		This:     m.managedObjectId,
+		Count:    int32(len(contacts)),
+		Contacts: strings.Join(packed, ","),
+		ScanTime: scanTime,
+	}
+
+	_, err := m.virtualbox.IMouseputEventMultiTouchString(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// MoveTo moves the pointer to the zero-based pixel coordinates x and y.
+func (m *Mouse) MoveTo(x, y int32) error {
+	return m.PutMouseEventAbsolute(x+1, y+1, 0, 0, 0)
+}
+
+// Click moves the pointer to the zero-based pixel coordinates x and y and
+// clicks buttons there. If ctx is done while the buttons are held, they are
+// released at once and ctx.Err() is returned.
+func (m *Mouse) Click(ctx context.Context, x, y int32, buttons MouseButtonState) error {
+	if err := m.PutMouseEventAbsolute(x+1, y+1, 0, 0, 0); err != nil {
+		return err
+	}
+	if err := m.PutMouseEventAbsolute(x+1, y+1, 0, 0, buttons); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		if err := m.PutMouseEventAbsolute(x+1, y+1, 0, 0, 0); err != nil {
+			return err
+		}
+		return ctx.Err()
+	case <-time.After(mouseClickDuration):
+	}
+	return m.PutMouseEventAbsolute(x+1, y+1, 0, 0, 0)
+}
+
+// GetEventSource returns the event source of the mouse, which delivers
+// pointer shape and mouse capability events.
+func (m *Mouse) GetEventSource() (*EventSource, error) {
+	request := vboxweb.IMousegetEventSource{This: m.managedObjectId}
+
+	response, err := m.virtualbox.IMousegetEventSource(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &EventSource{virtualbox: m.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// Events subscribes to the event source of the mouse. See
+// EventSource.Events.
+func (m *Mouse) Events(ctx context.Context, types ...vboxweb.VBoxEventType) (<-chan Event, error) {
+	es, err := m.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.events(ctx, true, types)
+}
+
+func (m *Mouse) Release() error {
+	return m.virtualbox.Release(m.managedObjectId)
+}
@@ -0,0 +1,26 @@
+package vboxapi
+
+import "testing"
+
+func TestTouchContactPack(t *testing.T) {
+	tests := []struct {
+		c    TouchContact
+		want int64
+	}{
+		{TouchContact{}, 0},
+		{TouchContact{X: 0x1234, Y: 0x5678}, 0x56781234},
+		{TouchContact{ID: 7}, 0x07_0000_0000},
+		{TouchContact{InContact: true}, 0x01_00_0000_0000},
+		{TouchContact{InRange: true}, 0x02_00_0000_0000},
+		{
+			TouchContact{X: 0xFFFF, Y: 0xFFFF, ID: 0xFF, InContact: true, InRange: true},
+			0x03_FF_FFFF_FFFF,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.c.pack(); got != tt.want {
+			t.Errorf("%+v.pack() = %#x, want %#x", tt.c, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package vboxapi
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// Alias mode flags of the NAT engine, see NATEngine.SetAliasMode.
+const (
+	NATAliasLog          uint32 = 0x1
+	NATAliasProxyOnly    uint32 = 0x2
+	NATAliasUseSamePorts uint32 = 0x4
+)
+
+// NATEngine is the NAT engine of a network adapter attached to NAT.
+type NATEngine struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// NATRedirect is a port forwarding rule of a NAT engine.
+type NATRedirect struct {
+	Name      string
+	Protocol  vboxweb.NATProtocol
+	HostIP    string
+	HostPort  uint16
+	GuestIP   string
+	GuestPort uint16
+}
+
+// parseNATRedirect parses a rule in the "name,proto,hostip,hostport,guestip,guestport"
+// form returned by INATEngine::getRedirects, where proto is 0 for UDP and
+// 1 for TCP.
+func parseNATRedirect(s string) (*NATRedirect, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("malformed NAT redirect %q", s)
+	}
+
+	r := &NATRedirect{Name: fields[0], HostIP: fields[2], GuestIP: fields[4]}
+
+	switch fields[1] {
+	case "0":
+		r.Protocol = vboxweb.NATProtocolUDP
+	case "1":
+		r.Protocol = vboxweb.NATProtocolTCP
+	default:
+		return nil, fmt.Errorf("unknown protocol in NAT redirect %q", s)
+	}
+
+	hostPort, err := strconv.ParseUint(fields[3], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("bad host port in NAT redirect %q", s)
+	}
+	r.HostPort = uint16(hostPort)
+
+	guestPort, err := strconv.ParseUint(fields[5], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("bad guest port in NAT redirect %q", s)
+	}
+	r.GuestPort = uint16(guestPort)
+
+	return r, nil
+}
+
+// NATEngine returns the NAT engine of the adapter.
+func (na *NetworkAdapter) NATEngine() (*NATEngine, error) {
+	request := vboxweb.INetworkAdaptergetNATEngine{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetNATEngine(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &NATEngine{virtualbox: na.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// AddRedirect adds a port forwarding rule. Empty hostIP and guestIP mean any
+// host address and the guest's DHCP address.
+func (ne *NATEngine) AddRedirect(name string, proto vboxweb.NATProtocol, hostIP string, hostPort uint16, guestIP string, guestPort uint16) error {
+	request := vboxweb.INATEngineaddRedirect{
+		This:      ne.managedObjectId,
+		Name:      name,
+		Proto:     &proto,
+		HostIP:    hostIP,
+		HostPort:  hostPort,
+		GuestIP:   guestIP,
+		GuestPort: guestPort,
+	}
+
+	_, err := ne.virtualbox.INATEngineaddRedirect(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) RemoveRedirect(name string) error {
+	request := vboxweb.INATEngineremoveRedirect{This: ne.managedObjectId, Name: name}
+
+	_, err := ne.virtualbox.INATEngineremoveRedirect(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Redirects returns the port forwarding rules of the engine.
+func (ne *NATEngine) Redirects() ([]*NATRedirect, error) {
+	request := vboxweb.INATEnginegetRedirects{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetRedirects(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	redirects := make([]*NATRedirect, len(response.Returnval))
+	for i, s := range response.Returnval {
+		r, err := parseNATRedirect(s)
+		if err != nil {
+			return nil, err
+		}
+		redirects[i] = r
+	}
+
+	return redirects, nil
+}
+
+func (ne *NATEngine) GetNetwork() (string, error) {
+	request := vboxweb.INATEnginegetNetwork{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetNetwork(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetNetwork(network string) error {
+	request := vboxweb.INATEnginesetNetwork{This: ne.managedObjectId, Network: network}
+
+	_, err := ne.virtualbox.INATEnginesetNetwork(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetHostIP() (string, error) {
+	request := vboxweb.INATEnginegetHostIP{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetHostIP(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetHostIP(hostIP string) error {
+	request := vboxweb.INATEnginesetHostIP{This: ne.managedObjectId, HostIP: hostIP}
+
+	_, err := ne.virtualbox.INATEnginesetHostIP(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetTFTPPrefix() (string, error) {
+	request := vboxweb.INATEnginegetTFTPPrefix{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetTFTPPrefix(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetTFTPPrefix(tftpPrefix string) error {
+	request := vboxweb.INATEnginesetTFTPPrefix{This: ne.managedObjectId, TFTPPrefix: tftpPrefix}
+
+	_, err := ne.virtualbox.INATEnginesetTFTPPrefix(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetTFTPBootFile() (string, error) {
+	request := vboxweb.INATEnginegetTFTPBootFile{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetTFTPBootFile(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetTFTPBootFile(tftpBootFile string) error {
+	request := vboxweb.INATEnginesetTFTPBootFile{This: ne.managedObjectId, TFTPBootFile: tftpBootFile}
+
+	_, err := ne.virtualbox.INATEnginesetTFTPBootFile(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetTFTPNextServer() (string, error) {
+	request := vboxweb.INATEnginegetTFTPNextServer{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetTFTPNextServer(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetTFTPNextServer(tftpNextServer string) error {
+	request := vboxweb.INATEnginesetTFTPNextServer{This: ne.managedObjectId, TFTPNextServer: tftpNextServer}
+
+	_, err := ne.virtualbox.INATEnginesetTFTPNextServer(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetAliasMode() (uint32, error) {
+	request := vboxweb.INATEnginegetAliasMode{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetAliasMode(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetAliasMode(aliasMode uint32) error {
+	request := vboxweb.INATEnginesetAliasMode{This: ne.managedObjectId, AliasMode: aliasMode}
+
+	_, err := ne.virtualbox.INATEnginesetAliasMode(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetDNSPassDomain() (bool, error) {
+	request := vboxweb.INATEnginegetDNSPassDomain{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetDNSPassDomain(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetDNSPassDomain(dnsPassDomain bool) error {
+	request := vboxweb.INATEnginesetDNSPassDomain{This: ne.managedObjectId, DNSPassDomain: dnsPassDomain}
+
+	_, err := ne.virtualbox.INATEnginesetDNSPassDomain(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetDNSProxy() (bool, error) {
+	request := vboxweb.INATEnginegetDNSProxy{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetDNSProxy(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetDNSProxy(dnsProxy bool) error {
+	request := vboxweb.INATEnginesetDNSProxy{This: ne.managedObjectId, DNSProxy: dnsProxy}
+
+	_, err := ne.virtualbox.INATEnginesetDNSProxy(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) GetDNSUseHostResolver() (bool, error) {
+	request := vboxweb.INATEnginegetDNSUseHostResolver{This: ne.managedObjectId}
+
+	response, err := ne.virtualbox.INATEnginegetDNSUseHostResolver(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (ne *NATEngine) SetDNSUseHostResolver(dnsUseHostResolver bool) error {
+	request := vboxweb.INATEnginesetDNSUseHostResolver{This: ne.managedObjectId, DNSUseHostResolver: dnsUseHostResolver}
+
+	_, err := ne.virtualbox.INATEnginesetDNSUseHostResolver(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (ne *NATEngine) Release() error {
+	return ne.virtualbox.Release(ne.managedObjectId)
+}
+
+// ForwardPort adds a TCP port forwarding rule named name from hostPort on
+// 127.0.0.1 to guestPort on the NAT adapter in slot. A hostPort of 0 picks a
+// free port with FreeLocalPort; the returned rule holds the port used.
+func (m *Machine) ForwardPort(slot uint32, name string, hostPort, guestPort uint16) (*NATRedirect, error) {
+	if hostPort == 0 {
+		port, err := FreeLocalPort()
+		if err != nil {
+			return nil, err
+		}
+		hostPort = port
+	}
+
+	r := &NATRedirect{
+		Name:      name,
+		Protocol:  vboxweb.NATProtocolTCP,
+		HostIP:    "127.0.0.1",
+		HostPort:  hostPort,
+		GuestPort: guestPort,
+	}
+
+	err := m.withSessionMachine(func(sm *Machine) error {
+		na, err := sm.GetNetworkAdapter(slot)
+		if err != nil {
+			return err
+		}
+		defer na.Release()
+
+		at, err := na.GetAttachmentType()
+		if err != nil {
+			return err
+		}
+		if at == nil || *at != vboxweb.NetworkAttachmentTypeNAT {
+			return errors.New("network adapter is not attached to NAT")
+		}
+
+		ne, err := na.NATEngine()
+		if err != nil {
+			return err
+		}
+		defer ne.Release()
+
+		return ne.AddRedirect(r.Name, r.Protocol, r.HostIP, r.HostPort, r.GuestIP, r.GuestPort)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// FreeLocalPort asks the local host for a free TCP port on 127.0.0.1. The
+// answer only holds for the host vboxwebsrv runs on if that is the local
+// host, and the port may be taken by another process before it is used.
+func FreeLocalPort() (uint16, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+
+	return uint16(l.Addr().(*net.TCPAddr).Port), nil
+}
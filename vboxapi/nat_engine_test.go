@@ -0,0 +1,40 @@
+package vboxapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestParseNATRedirect(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *NATRedirect
+		wantErr bool
+	}{
+		{
+			in:   "ssh,1,127.0.0.1,2222,,22",
+			want: &NATRedirect{Name: "ssh", Protocol: vboxweb.NATProtocolTCP, HostIP: "127.0.0.1", HostPort: 2222, GuestPort: 22},
+		},
+		{
+			in:   "dns,0,,5353,10.0.2.15,53",
+			want: &NATRedirect{Name: "dns", Protocol: vboxweb.NATProtocolUDP, HostPort: 5353, GuestIP: "10.0.2.15", GuestPort: 53},
+		},
+		{in: "ssh,1,127.0.0.1,2222,,", wantErr: true},
+		{in: "ssh,2,127.0.0.1,2222,,22", wantErr: true},
+		{in: "ssh,1,127.0.0.1,70000,,22", wantErr: true},
+		{in: "ssh,1,127.0.0.1,2222", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNATRedirect(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNATRedirect(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseNATRedirect(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package vboxapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// NATNetwork is a VirtualBox NAT network shared by several machines.
+type NATNetwork struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// NATLocalMapping maps a host loopback address to an offset in the NAT
+// network.
+type NATLocalMapping struct {
+	HostID string
+	Offset int32
+}
+
+// natNetworkRuleRe matches port forwarding rules of a NAT network in the
+// "name:proto:[hostip]:hostport:[guestip]:guestport" form.
+var natNetworkRuleRe = regexp.MustCompile(`^(.*):(tcp|udp):\[([^\]]*)\]:(\d+):\[([^\]]*)\]:(\d+)$`)
+
+func parseNATNetworkRule(s string) (*NATRedirect, error) {
+	m := natNetworkRuleRe.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("malformed NAT network rule %q", s)
+	}
+
+	r := &NATRedirect{Name: m[1], HostIP: m[3], GuestIP: m[5]}
+	if m[2] == "udp" {
+		r.Protocol = vboxweb.NATProtocolUDP
+	} else {
+		r.Protocol = vboxweb.NATProtocolTCP
+	}
+
+	hostPort, err := strconv.ParseUint(m[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("bad host port in NAT network rule %q", s)
+	}
+	r.HostPort = uint16(hostPort)
+
+	guestPort, err := strconv.ParseUint(m[6], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("bad guest port in NAT network rule %q", s)
+	}
+	r.GuestPort = uint16(guestPort)
+
+	return r, nil
+}
+
+func (nn *NATNetwork) GetNetworkName() (string, error) {
+	request := vboxweb.INATNetworkgetNetworkName{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetNetworkName(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetNetworkName(networkName string) error {
+	request := vboxweb.INATNetworksetNetworkName{This: nn.managedObjectId, NetworkName: networkName}
+
+	_, err := nn.virtualbox.INATNetworksetNetworkName(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) GetEnabled() (bool, error) {
+	request := vboxweb.INATNetworkgetEnabled{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetEnabled(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetEnabled(enabled bool) error {
+	request := vboxweb.INATNetworksetEnabled{This: nn.managedObjectId, Enabled: enabled}
+
+	_, err := nn.virtualbox.INATNetworksetEnabled(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) GetNetwork() (string, error) {
+	request := vboxweb.INATNetworkgetNetwork{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetNetwork(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetNetwork(network string) error {
+	request := vboxweb.INATNetworksetNetwork{This: nn.managedObjectId, Network: network}
+
+	_, err := nn.virtualbox.INATNetworksetNetwork(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) GetGateway() (string, error) {
+	request := vboxweb.INATNetworkgetGateway{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetGateway(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) GetIPv6Enabled() (bool, error) {
+	request := vboxweb.INATNetworkgetIPv6Enabled{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetIPv6Enabled(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetIPv6Enabled(ipv6Enabled bool) error {
+	request := vboxweb.INATNetworksetIPv6Enabled{This: nn.managedObjectId, IPv6Enabled: ipv6Enabled}
+
+	_, err := nn.virtualbox.INATNetworksetIPv6Enabled(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) GetIPv6Prefix() (string, error) {
+	request := vboxweb.INATNetworkgetIPv6Prefix{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetIPv6Prefix(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetIPv6Prefix(ipv6Prefix string) error {
+	request := vboxweb.INATNetworksetIPv6Prefix{This: nn.managedObjectId, IPv6Prefix: ipv6Prefix}
+
+	_, err := nn.virtualbox.INATNetworksetIPv6Prefix(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) GetAdvertiseDefaultIPv6RouteEnabled() (bool, error) {
+	request := vboxweb.INATNetworkgetAdvertiseDefaultIPv6RouteEnabled{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetAdvertiseDefaultIPv6RouteEnabled(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetAdvertiseDefaultIPv6RouteEnabled(enabled bool) error {
+	request := vboxweb.INATNetworksetAdvertiseDefaultIPv6RouteEnabled{This: nn.managedObjectId, AdvertiseDefaultIPv6RouteEnabled: enabled}
+
+	_, err := nn.virtualbox.INATNetworksetAdvertiseDefaultIPv6RouteEnabled(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) GetNeedDhcpServer() (bool, error) {
+	request := vboxweb.INATNetworkgetNeedDhcpServer{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetNeedDhcpServer(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetNeedDhcpServer(needDhcpServer bool) error {
+	request := vboxweb.INATNetworksetNeedDhcpServer{This: nn.managedObjectId, NeedDhcpServer: needDhcpServer}
+
+	_, err := nn.virtualbox.INATNetworksetNeedDhcpServer(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) GetLoopbackIp6() (int32, error) {
+	request := vboxweb.INATNetworkgetLoopbackIp6{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetLoopbackIp6(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (nn *NATNetwork) SetLoopbackIp6(loopbackIp6 int32) error {
+	request := vboxweb.INATNetworksetLoopbackIp6{This: nn.managedObjectId, LoopbackIp6: loopbackIp6}
+
+	_, err := nn.virtualbox.INATNetworksetLoopbackIp6(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// AddPortForwardRule adds a port forwarding rule to the network.
+func (nn *NATNetwork) AddPortForwardRule(ipv6 bool, r *NATRedirect) error {
+	proto := r.Protocol
+	request := vboxweb.INATNetworkaddPortForwardRule{
+		This:      nn.managedObjectId,
+		IsIpv6:    ipv6,
+		RuleName:  r.Name,
+		Proto:     &proto,
+		HostIP:    r.HostIP,
+		HostPort:  r.HostPort,
+		GuestIP:   r.GuestIP,
+		GuestPort: r.GuestPort,
+	}
+
+	_, err := nn.virtualbox.INATNetworkaddPortForwardRule(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) RemovePortForwardRule(ipv6 bool, name string) error {
+	request := vboxweb.INATNetworkremovePortForwardRule{This: nn.managedObjectId, ISipv6: ipv6, RuleName: name}
+
+	_, err := nn.virtualbox.INATNetworkremovePortForwardRule(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// PortForwardRules returns the IPv4 or IPv6 port forwarding rules of the
+// network.
+func (nn *NATNetwork) PortForwardRules(ipv6 bool) ([]*NATRedirect, error) {
+	var rules []string
+	if ipv6 {
+		request := vboxweb.INATNetworkgetPortForwardRules6{This: nn.managedObjectId}
+
+		response, err := nn.virtualbox.INATNetworkgetPortForwardRules6(&request)
+		if err != nil {
+			return nil, err // TODO: Wrap the error
+		}
+		rules = response.Returnval
+	} else {
+		request := vboxweb.INATNetworkgetPortForwardRules4{This: nn.managedObjectId}
+
+		response, err := nn.virtualbox.INATNetworkgetPortForwardRules4(&request)
+		if err != nil {
+			return nil, err // TODO: Wrap the error
+		}
+		rules = response.Returnval
+	}
+
+	redirects := make([]*NATRedirect, len(rules))
+	for i, s := range rules {
+		r, err := parseNATNetworkRule(s)
+		if err != nil {
+			return nil, err
+		}
+		redirects[i] = r
+	}
+
+	return redirects, nil
+}
+
+func (nn *NATNetwork) AddLocalMapping(hostID string, offset int32) error {
+	request := vboxweb.INATNetworkaddLocalMapping{This: nn.managedObjectId, Hostid: hostID, Offset: offset}
+
+	_, err := nn.virtualbox.INATNetworkaddLocalMapping(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// LocalMappings returns the loopback mappings of the network.
+func (nn *NATNetwork) LocalMappings() ([]*NATLocalMapping, error) {
+	request := vboxweb.INATNetworkgetLocalMappings{This: nn.managedObjectId}
+
+	response, err := nn.virtualbox.INATNetworkgetLocalMappings(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	mappings := make([]*NATLocalMapping, len(response.Returnval))
+	for i, s := range response.Returnval {
+		hostID, offset := s, ""
+		if n := strings.LastIndex(s, "="); n >= 0 {
+			hostID, offset = s[:n], s[n+1:]
+		}
+		o, err := strconv.ParseInt(offset, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("malformed NAT local mapping %q", s)
+		}
+		mappings[i] = &NATLocalMapping{HostID: hostID, Offset: int32(o)}
+	}
+
+	return mappings, nil
+}
+
+// Start starts the NAT service of the network.
+func (nn *NATNetwork) Start(trunkType string) error {
+	request := vboxweb.INATNetworkstart{This: nn.managedObjectId, TrunkType: trunkType}
+
+	_, err := nn.virtualbox.INATNetworkstart(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) Stop() error {
+	request := vboxweb.INATNetworkstop{This: nn.managedObjectId}
+
+	_, err := nn.virtualbox.INATNetworkstop(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (nn *NATNetwork) Release() error {
+	return nn.virtualbox.Release(nn.managedObjectId)
+}
@@ -0,0 +1,40 @@
+package vboxapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+func TestParseNATNetworkRule(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *NATRedirect
+		wantErr bool
+	}{
+		{
+			in:   "ssh:tcp:[]:2222:[10.0.2.15]:22",
+			want: &NATRedirect{Name: "ssh", Protocol: vboxweb.NATProtocolTCP, HostPort: 2222, GuestIP: "10.0.2.15", GuestPort: 22},
+		},
+		{
+			in:   "dns:udp:[127.0.0.1]:5353:[]:53",
+			want: &NATRedirect{Name: "dns", Protocol: vboxweb.NATProtocolUDP, HostIP: "127.0.0.1", HostPort: 5353, GuestPort: 53},
+		},
+		{in: "ssh:sctp:[]:2222:[]:22", wantErr: true},
+		{in: "ssh:tcp:[]:70000:[]:22", wantErr: true},
+		{in: "ssh:tcp:[]:2222:[]:99999", wantErr: true},
+		{in: "ssh:tcp::2222::22", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseNATNetworkRule(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNATNetworkRule(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseNATNetworkRule(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package vboxapi
 
 import (
+	"errors"
+
 	"github.com/blacktop/go-vboxapi/vboxweb"
 )
 
@@ -9,6 +11,98 @@ type NetworkAdapter struct {
 	managedObjectId string
 }
 
+// NetworkAdapterConfig is the configuration of a network adapter slot of a
+// machine. It is read by Machine.NetworkAdapters and written back by Apply.
+type NetworkAdapterConfig struct {
+	machine *Machine
+
+	Slot              uint32
+	Enabled           bool
+	AdapterType       vboxweb.NetworkAdapterType
+	MACAddress        string
+	AttachmentType    vboxweb.NetworkAttachmentType
+	BridgedInterface  string
+	HostOnlyInterface string
+	InternalNetwork   string
+	NATNetwork        string
+	GenericDriver     string
+	// Properties holds the generic driver properties.
+	Properties        map[string]string
+	CableConnected    bool
+	LineSpeed         uint32
+	PromiscModePolicy vboxweb.NetworkAdapterPromiscModePolicy
+	TraceEnabled      bool
+	TraceFile         string
+	BootPriority      uint32
+	// BandwidthGroup is the name of the bandwidth group, if any.
+	BandwidthGroup string
+}
+
+// Apply writes the configuration to its slot of the machine it was read
+// from. Only settings that differ from the current ones are changed, so
+// attachment changes can be applied to a running VM.
+func (c *NetworkAdapterConfig) Apply() error {
+	if c.machine == nil {
+		return errors.New("network adapter config not read from a machine")
+	}
+	return c.machine.ApplyNetworkAdapters(c)
+}
+
+func (na *NetworkAdapter) GetSlot() (uint32, error) {
+	request := vboxweb.INetworkAdaptergetSlot{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetSlot(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) GetEnabled() (bool, error) {
+	request := vboxweb.INetworkAdaptergetEnabled{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetEnabled(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetEnabled(enabled bool) error {
+	request := vboxweb.INetworkAdaptersetEnabled{This: na.managedObjectId, Enabled: enabled}
+
+	_, err := na.virtualbox.INetworkAdaptersetEnabled(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetAdapterType() (*vboxweb.NetworkAdapterType, error) {
+	request := vboxweb.INetworkAdaptergetAdapterType{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetAdapterType(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetAdapterType(adapterType vboxweb.NetworkAdapterType) error {
+	request := vboxweb.INetworkAdaptersetAdapterType{This: na.managedObjectId, AdapterType: &adapterType}
+
+	_, err := na.virtualbox.INetworkAdaptersetAdapterType(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
 func (na *NetworkAdapter) GetMACAddress() (string, error) {
 	request := vboxweb.INetworkAdaptergetMACAddress{This: na.managedObjectId}
 
@@ -19,3 +113,583 @@ func (na *NetworkAdapter) GetMACAddress() (string, error) {
 
 	return response.Returnval, nil
 }
+
+func (na *NetworkAdapter) SetMACAddress(macAddress string) error {
+	request := vboxweb.INetworkAdaptersetMACAddress{This: na.managedObjectId, MACAddress: macAddress}
+
+	_, err := na.virtualbox.INetworkAdaptersetMACAddress(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetAttachmentType() (*vboxweb.NetworkAttachmentType, error) {
+	request := vboxweb.INetworkAdaptergetAttachmentType{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetAttachmentType(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetAttachmentType(attachmentType vboxweb.NetworkAttachmentType) error {
+	request := vboxweb.INetworkAdaptersetAttachmentType{This: na.managedObjectId, AttachmentType: &attachmentType}
+
+	_, err := na.virtualbox.INetworkAdaptersetAttachmentType(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetBridgedInterface() (string, error) {
+	request := vboxweb.INetworkAdaptergetBridgedInterface{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetBridgedInterface(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetBridgedInterface(bridgedInterface string) error {
+	request := vboxweb.INetworkAdaptersetBridgedInterface{This: na.managedObjectId, BridgedInterface: bridgedInterface}
+
+	_, err := na.virtualbox.INetworkAdaptersetBridgedInterface(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetHostOnlyInterface() (string, error) {
+	request := vboxweb.INetworkAdaptergetHostOnlyInterface{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetHostOnlyInterface(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetHostOnlyInterface(hostOnlyInterface string) error {
+	request := vboxweb.INetworkAdaptersetHostOnlyInterface{This: na.managedObjectId, HostOnlyInterface: hostOnlyInterface}
+
+	_, err := na.virtualbox.INetworkAdaptersetHostOnlyInterface(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetInternalNetwork() (string, error) {
+	request := vboxweb.INetworkAdaptergetInternalNetwork{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetInternalNetwork(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetInternalNetwork(internalNetwork string) error {
+	request := vboxweb.INetworkAdaptersetInternalNetwork{This: na.managedObjectId, InternalNetwork: internalNetwork}
+
+	_, err := na.virtualbox.INetworkAdaptersetInternalNetwork(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetNATNetwork() (string, error) {
+	request := vboxweb.INetworkAdaptergetNATNetwork{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetNATNetwork(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetNATNetwork(natNetwork string) error {
+	request := vboxweb.INetworkAdaptersetNATNetwork{This: na.managedObjectId, NATNetwork: natNetwork}
+
+	_, err := na.virtualbox.INetworkAdaptersetNATNetwork(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetGenericDriver() (string, error) {
+	request := vboxweb.INetworkAdaptergetGenericDriver{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetGenericDriver(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetGenericDriver(genericDriver string) error {
+	request := vboxweb.INetworkAdaptersetGenericDriver{This: na.managedObjectId, GenericDriver: genericDriver}
+
+	_, err := na.virtualbox.INetworkAdaptersetGenericDriver(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetCableConnected() (bool, error) {
+	request := vboxweb.INetworkAdaptergetCableConnected{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetCableConnected(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetCableConnected(cableConnected bool) error {
+	request := vboxweb.INetworkAdaptersetCableConnected{This: na.managedObjectId, CableConnected: cableConnected}
+
+	_, err := na.virtualbox.INetworkAdaptersetCableConnected(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetLineSpeed() (uint32, error) {
+	request := vboxweb.INetworkAdaptergetLineSpeed{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetLineSpeed(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetLineSpeed(lineSpeed uint32) error {
+	request := vboxweb.INetworkAdaptersetLineSpeed{This: na.managedObjectId, LineSpeed: lineSpeed}
+
+	_, err := na.virtualbox.INetworkAdaptersetLineSpeed(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetPromiscModePolicy() (*vboxweb.NetworkAdapterPromiscModePolicy, error) {
+	request := vboxweb.INetworkAdaptergetPromiscModePolicy{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetPromiscModePolicy(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetPromiscModePolicy(promiscModePolicy vboxweb.NetworkAdapterPromiscModePolicy) error {
+	request := vboxweb.INetworkAdaptersetPromiscModePolicy{This: na.managedObjectId, PromiscModePolicy: &promiscModePolicy}
+
+	_, err := na.virtualbox.INetworkAdaptersetPromiscModePolicy(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetTraceEnabled() (bool, error) {
+	request := vboxweb.INetworkAdaptergetTraceEnabled{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetTraceEnabled(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetTraceEnabled(traceEnabled bool) error {
+	request := vboxweb.INetworkAdaptersetTraceEnabled{This: na.managedObjectId, TraceEnabled: traceEnabled}
+
+	_, err := na.virtualbox.INetworkAdaptersetTraceEnabled(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetTraceFile() (string, error) {
+	request := vboxweb.INetworkAdaptergetTraceFile{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetTraceFile(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetTraceFile(traceFile string) error {
+	request := vboxweb.INetworkAdaptersetTraceFile{This: na.managedObjectId, TraceFile: traceFile}
+
+	_, err := na.virtualbox.INetworkAdaptersetTraceFile(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetBootPriority() (uint32, error) {
+	request := vboxweb.INetworkAdaptergetBootPriority{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetBootPriority(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (na *NetworkAdapter) SetBootPriority(bootPriority uint32) error {
+	request := vboxweb.INetworkAdaptersetBootPriority{This: na.managedObjectId, BootPriority: bootPriority}
+
+	_, err := na.virtualbox.INetworkAdaptersetBootPriority(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) GetBandwidthGroup() (*BandwidthGroup, error) {
+	request := vboxweb.INetworkAdaptergetBandwidthGroup{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetBandwidthGroup(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	if response.Returnval == "" {
+		return nil, nil
+	}
+	return &BandwidthGroup{virtualbox: na.virtualbox, managedObjectId: response.Returnval}, nil
+}
+
+// SetBandwidthGroup assigns the adapter to bg, or removes it from its
+// bandwidth group if bg is nil.
+func (na *NetworkAdapter) SetBandwidthGroup(bg *BandwidthGroup) error {
+	request := vboxweb.INetworkAdaptersetBandwidthGroup{This: na.managedObjectId}
+	if bg != nil {
+		request.BandwidthGroup = bg.managedObjectId
+	}
+
+	_, err := na.virtualbox.INetworkAdaptersetBandwidthGroup(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// GetProperties returns the generic driver properties of the adapter.
+func (na *NetworkAdapter) GetProperties() (map[string]string, error) {
+	request := vboxweb.INetworkAdaptergetProperties{This: na.managedObjectId}
+
+	response, err := na.virtualbox.INetworkAdaptergetProperties(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	properties := make(map[string]string, len(response.ReturnNames))
+	for i, name := range response.ReturnNames {
+		if i < len(response.Returnval) {
+			properties[name] = response.Returnval[i]
+		}
+	}
+
+	return properties, nil
+}
+
+// SetProperty sets a generic driver property. An empty value deletes it.
+func (na *NetworkAdapter) SetProperty(key, value string) error {
+	request := vboxweb.INetworkAdaptersetProperty{This: na.managedObjectId, Key: key, Value: value}
+
+	_, err := na.virtualbox.INetworkAdaptersetProperty(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Config reads the full configuration of the adapter.
+func (na *NetworkAdapter) Config() (*NetworkAdapterConfig, error) {
+	var err error
+	c := &NetworkAdapterConfig{}
+
+	c.Slot, err = na.GetSlot()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Enabled, err = na.GetEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := na.GetAdapterType()
+	if err != nil {
+		return nil, err
+	}
+	if at != nil {
+		c.AdapterType = *at
+	}
+
+	c.MACAddress, err = na.GetMACAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	nat, err := na.GetAttachmentType()
+	if err != nil {
+		return nil, err
+	}
+	if nat != nil {
+		c.AttachmentType = *nat
+	}
+
+	c.BridgedInterface, err = na.GetBridgedInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	c.HostOnlyInterface, err = na.GetHostOnlyInterface()
+	if err != nil {
+		return nil, err
+	}
+
+	c.InternalNetwork, err = na.GetInternalNetwork()
+	if err != nil {
+		return nil, err
+	}
+
+	c.NATNetwork, err = na.GetNATNetwork()
+	if err != nil {
+		return nil, err
+	}
+
+	c.GenericDriver, err = na.GetGenericDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Properties, err = na.GetProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	c.CableConnected, err = na.GetCableConnected()
+	if err != nil {
+		return nil, err
+	}
+
+	c.LineSpeed, err = na.GetLineSpeed()
+	if err != nil {
+		return nil, err
+	}
+
+	pmp, err := na.GetPromiscModePolicy()
+	if err != nil {
+		return nil, err
+	}
+	if pmp != nil {
+		c.PromiscModePolicy = *pmp
+	}
+
+	c.TraceEnabled, err = na.GetTraceEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	c.TraceFile, err = na.GetTraceFile()
+	if err != nil {
+		return nil, err
+	}
+
+	c.BootPriority, err = na.GetBootPriority()
+	if err != nil {
+		return nil, err
+	}
+
+	bg, err := na.GetBandwidthGroup()
+	if err != nil {
+		return nil, err
+	}
+	if bg != nil {
+		defer bg.Release()
+		c.BandwidthGroup, err = bg.GetName()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// apply writes the settings of c that differ from cur to the adapter. bc is
+// used to look up the bandwidth group by name.
+func (na *NetworkAdapter) apply(c, cur *NetworkAdapterConfig, bc *BandwidthControl) error {
+	if c.AdapterType != "" && c.AdapterType != cur.AdapterType {
+		if err := na.SetAdapterType(c.AdapterType); err != nil {
+			return err
+		}
+	}
+
+	if c.MACAddress != "" && c.MACAddress != cur.MACAddress {
+		if err := na.SetMACAddress(c.MACAddress); err != nil {
+			return err
+		}
+	}
+
+	if c.BridgedInterface != cur.BridgedInterface {
+		if err := na.SetBridgedInterface(c.BridgedInterface); err != nil {
+			return err
+		}
+	}
+
+	if c.HostOnlyInterface != cur.HostOnlyInterface {
+		if err := na.SetHostOnlyInterface(c.HostOnlyInterface); err != nil {
+			return err
+		}
+	}
+
+	if c.InternalNetwork != cur.InternalNetwork {
+		if err := na.SetInternalNetwork(c.InternalNetwork); err != nil {
+			return err
+		}
+	}
+
+	if c.NATNetwork != cur.NATNetwork {
+		if err := na.SetNATNetwork(c.NATNetwork); err != nil {
+			return err
+		}
+	}
+
+	if c.GenericDriver != cur.GenericDriver {
+		if err := na.SetGenericDriver(c.GenericDriver); err != nil {
+			return err
+		}
+	}
+
+	for key, value := range c.Properties {
+		if cur.Properties[key] != value {
+			if err := na.SetProperty(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	for key := range cur.Properties {
+		if _, ok := c.Properties[key]; !ok {
+			if err := na.SetProperty(key, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.AttachmentType != "" && c.AttachmentType != cur.AttachmentType {
+		if err := na.SetAttachmentType(c.AttachmentType); err != nil {
+			return err
+		}
+	}
+
+	if c.PromiscModePolicy != "" && c.PromiscModePolicy != cur.PromiscModePolicy {
+		if err := na.SetPromiscModePolicy(c.PromiscModePolicy); err != nil {
+			return err
+		}
+	}
+
+	if c.CableConnected != cur.CableConnected {
+		if err := na.SetCableConnected(c.CableConnected); err != nil {
+			return err
+		}
+	}
+
+	if c.LineSpeed != cur.LineSpeed {
+		if err := na.SetLineSpeed(c.LineSpeed); err != nil {
+			return err
+		}
+	}
+
+	if c.TraceFile != cur.TraceFile {
+		if err := na.SetTraceFile(c.TraceFile); err != nil {
+			return err
+		}
+	}
+
+	if c.TraceEnabled != cur.TraceEnabled {
+		if err := na.SetTraceEnabled(c.TraceEnabled); err != nil {
+			return err
+		}
+	}
+
+	if c.BootPriority != cur.BootPriority {
+		if err := na.SetBootPriority(c.BootPriority); err != nil {
+			return err
+		}
+	}
+
+	if c.BandwidthGroup != cur.BandwidthGroup {
+		var bg *BandwidthGroup
+		if c.BandwidthGroup != "" {
+			var err error
+			bg, err = bc.GetBandwidthGroup(c.BandwidthGroup)
+			if err != nil {
+				return err
+			}
+			defer bg.Release()
+		}
+		if err := na.SetBandwidthGroup(bg); err != nil {
+			return err
+		}
+	}
+
+	if c.Enabled != cur.Enabled {
+		if err := na.SetEnabled(c.Enabled); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (na *NetworkAdapter) Release() error {
+	return na.virtualbox.Release(na.managedObjectId)
+}
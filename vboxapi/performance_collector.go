@@ -0,0 +1,382 @@
+package vboxapi
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// MetricObject is an object metrics can be collected for: a *Host or a
+// *Machine.
+type MetricObject interface {
+	metricObjectID() string
+}
+
+func (h *Host) metricObjectID() string {
+	return h.managedObjectId
+}
+
+func (m *Machine) metricObjectID() string {
+	return m.managedObjectId
+}
+
+// PerformanceCollector collects host and VM metrics such as CPU/Load/User,
+// RAM/Usage/Used, Net/Rate/Rx or Disk/Usage/Used. Metric names may use
+// the "*" wildcard and the ":avg", ":min" and ":max" aggregate suffixes.
+type PerformanceCollector struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// PerformanceMetric describes a metric collected for an object.
+type PerformanceMetric struct {
+	Name         string
+	Object       string
+	Description  string
+	Period       uint32
+	Count        uint32
+	Unit         string
+	MinimumValue int32
+	MaximumValue int32
+}
+
+// MetricSample holds the values collected for a metric of an object, oldest
+// first. Values must be divided by Scale to get Unit, see Scaled.
+type MetricSample struct {
+	Name     string
+	Object   string
+	Unit     string
+	Scale    uint32
+	Sequence uint32
+	Values   []int32
+}
+
+// Scaled returns the values of the sample in Unit.
+func (s *MetricSample) Scaled() []float64 {
+	scale := float64(s.Scale)
+	if scale == 0 {
+		scale = 1
+	}
+	values := make([]float64, len(s.Values))
+	for i, v := range s.Values {
+		values[i] = float64(v) / scale
+	}
+	return values
+}
+
+// Last returns the most recent value of the sample in Unit, or 0 if the
+// sample holds no values.
+func (s *MetricSample) Last() float64 {
+	values := s.Scaled()
+	if len(values) == 0 {
+		return 0
+	}
+	return values[len(values)-1]
+}
+
+// GetPerformanceCollector returns the performance collector of VirtualBox.
+func (vb *VirtualBox) GetPerformanceCollector() (*PerformanceCollector, error) {
+	request := vboxweb.IVirtualBoxgetPerformanceCollector{This: vb.managedObjectId}
+
+	response, err := vb.IVirtualBoxgetPerformanceCollector(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &PerformanceCollector{virtualbox: vb, managedObjectId: response.Returnval}, nil
+}
+
+// GetMetricNames returns the names of all metrics VirtualBox can collect.
+func (pc *PerformanceCollector) GetMetricNames() ([]string, error) {
+	request := vboxweb.IPerformanceCollectorgetMetricNames{This: pc.managedObjectId}
+
+	response, err := pc.virtualbox.IPerformanceCollectorgetMetricNames(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GetMetrics describes the metrics matching names for the given objects.
+// Empty names or objects match all.
+func (pc *PerformanceCollector) GetMetrics(names []string, objects ...MetricObject) ([]*PerformanceMetric, error) {
+	request := vboxweb.IPerformanceCollectorgetMetrics{
+		This:        pc.managedObjectId,
+		MetricNames: metricNames(names),
+		Objects:     metricObjectIDs(objects),
+	}
+
+	response, err := pc.virtualbox.IPerformanceCollectorgetMetrics(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return pc.newPerformanceMetrics(response.Returnval)
+}
+
+// SetupMetrics starts collecting the metrics matching names for the given
+// objects every period seconds, keeping the last count values. Empty names
+// or objects match all.
+func (pc *PerformanceCollector) SetupMetrics(names []string, period, count uint32, objects ...MetricObject) ([]*PerformanceMetric, error) {
+	request := vboxweb.IPerformanceCollectorsetupMetrics{
+		This:        pc.managedObjectId,
+		MetricNames: metricNames(names),
+		Objects:     metricObjectIDs(objects),
+		Period:      period,
+		Count:       count,
+	}
+
+	response, err := pc.virtualbox.IPerformanceCollectorsetupMetrics(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return pc.newPerformanceMetrics(response.Returnval)
+}
+
+// EnableMetrics resumes collecting the metrics matching names for the given
+// objects.
+func (pc *PerformanceCollector) EnableMetrics(names []string, objects ...MetricObject) ([]*PerformanceMetric, error) {
+	request := vboxweb.IPerformanceCollectorenableMetrics{
+		This:        pc.managedObjectId,
+		MetricNames: metricNames(names),
+		Objects:     metricObjectIDs(objects),
+	}
+
+	response, err := pc.virtualbox.IPerformanceCollectorenableMetrics(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return pc.newPerformanceMetrics(response.Returnval)
+}
+
+// DisableMetrics suspends collecting the metrics matching names for the
+// given objects.
+func (pc *PerformanceCollector) DisableMetrics(names []string, objects ...MetricObject) ([]*PerformanceMetric, error) {
+	request := vboxweb.IPerformanceCollectordisableMetrics{
+		This:        pc.managedObjectId,
+		MetricNames: metricNames(names),
+		Objects:     metricObjectIDs(objects),
+	}
+
+	response, err := pc.virtualbox.IPerformanceCollectordisableMetrics(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return pc.newPerformanceMetrics(response.Returnval)
+}
+
+// QueryMetricsData reads the values collected so far for the metrics
+// matching names for the given objects. Empty names or objects match all.
+func (pc *PerformanceCollector) QueryMetricsData(names []string, objects ...MetricObject) ([]*MetricSample, error) {
+	request := vboxweb.IPerformanceCollectorqueryMetricsData{
+		This:        pc.managedObjectId,
+		MetricNames: metricNames(names),
+		Objects:     metricObjectIDs(objects),
+	}
+
+	response, err := pc.virtualbox.IPerformanceCollectorqueryMetricsData(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	samples := make([]*MetricSample, len(response.ReturnMetricNames))
+	for i, name := range response.ReturnMetricNames {
+		s := &MetricSample{Name: name}
+		if i < len(response.ReturnObjects) {
+			s.Object = response.ReturnObjects[i]
+		}
+		if i < len(response.ReturnUnits) {
+			s.Unit = response.ReturnUnits[i]
+		}
+		if i < len(response.ReturnScales) {
+			s.Scale = response.ReturnScales[i]
+		}
+		if i < len(response.ReturnSequenceNumbers) {
+			s.Sequence = response.ReturnSequenceNumbers[i]
+		}
+		if i < len(response.ReturnDataIndices) && i < len(response.ReturnDataLengths) {
+			start := response.ReturnDataIndices[i]
+			end := start + response.ReturnDataLengths[i]
+			if int(end) <= len(response.Returnval) {
+				s.Values = response.Returnval[start:end]
+			}
+		}
+		samples[i] = s
+	}
+	return samples, nil
+}
+
+// NewSampler sets up the metrics matching names for the given objects and
+// queries them every interval, rounded to whole seconds, delivering each
+// round of samples on the sampler's channel. The sampler takes ownership of
+// pc and releases it when it stops, or right away if the setup fails.
+func (pc *PerformanceCollector) NewSampler(ctx context.Context, interval time.Duration, names []string, objects ...MetricObject) (*MetricsSampler, error) {
+	period := uint32(interval / time.Second)
+	if period == 0 {
+		period = 1
+	}
+	if _, err := pc.SetupMetrics(names, period, 1, objects...); err != nil {
+		pc.Release()
+		return nil, err
+	}
+
+	c := make(chan []*MetricSample, 1)
+	s := &MetricsSampler{C: c}
+	go s.run(ctx, c, pc, time.Duration(period)*time.Second, names, objects)
+	return s, nil
+}
+
+// Release releases the managed object reference of the collector.
+func (pc *PerformanceCollector) Release() error {
+	return pc.virtualbox.Release(pc.managedObjectId)
+}
+
+// MetricsSampler delivers metric samples at a fixed interval. C is closed
+// when the sampler's context is done or a query fails; Err then reports
+// the failure.
+type MetricsSampler struct {
+	C <-chan []*MetricSample
+
+	mu  sync.Mutex
+	err error
+}
+
+// Err returns the error that stopped the sampler, if any.
+func (s *MetricsSampler) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *MetricsSampler) run(ctx context.Context, c chan<- []*MetricSample, pc *PerformanceCollector, interval time.Duration, names []string, objects []MetricObject) {
+	defer close(c)
+	defer pc.Release()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		samples, err := pc.QueryMetricsData(names, objects...)
+		if err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+			return
+		}
+
+		select {
+		case c <- samples:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// newPerformanceMetrics reads and releases the IPerformanceMetric objects
+// moids.
+func (pc *PerformanceCollector) newPerformanceMetrics(moids []string) ([]*PerformanceMetric, error) {
+	metrics := make([]*PerformanceMetric, 0, len(moids))
+	for i, moid := range moids {
+		pm, err := pc.newPerformanceMetric(moid)
+		pc.virtualbox.Release(moid)
+		if err != nil {
+			for _, moid := range moids[i+1:] {
+				pc.virtualbox.Release(moid)
+			}
+			return nil, err
+		}
+		metrics = append(metrics, pm)
+	}
+	return metrics, nil
+}
+
+func (pc *PerformanceCollector) newPerformanceMetric(moid string) (*PerformanceMetric, error) {
+	vb := pc.virtualbox
+	pm := &PerformanceMetric{}
+
+	nameRequest := vboxweb.IPerformanceMetricgetMetricName{This: moid}
+	nameResponse, err := vb.IPerformanceMetricgetMetricName(&nameRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.Name = nameResponse.Returnval
+
+	objectRequest := vboxweb.IPerformanceMetricgetObject{This: moid}
+	objectResponse, err := vb.IPerformanceMetricgetObject(&objectRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.Object = objectResponse.Returnval
+
+	descriptionRequest := vboxweb.IPerformanceMetricgetDescription{This: moid}
+	descriptionResponse, err := vb.IPerformanceMetricgetDescription(&descriptionRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.Description = descriptionResponse.Returnval
+
+	periodRequest := vboxweb.IPerformanceMetricgetPeriod{This: moid}
+	periodResponse, err := vb.IPerformanceMetricgetPeriod(&periodRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.Period = periodResponse.Returnval
+
+	countRequest := vboxweb.IPerformanceMetricgetCount{This: moid}
+	countResponse, err := vb.IPerformanceMetricgetCount(&countRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.Count = countResponse.Returnval
+
+	unitRequest := vboxweb.IPerformanceMetricgetUnit{This: moid}
+	unitResponse, err := vb.IPerformanceMetricgetUnit(&unitRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.Unit = unitResponse.Returnval
+
+	minRequest := vboxweb.IPerformanceMetricgetMinimumValue{This: moid}
+	minResponse, err := vb.IPerformanceMetricgetMinimumValue(&minRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.MinimumValue = minResponse.Returnval
+
+	maxRequest := vboxweb.IPerformanceMetricgetMaximumValue{This: moid}
+	maxResponse, err := vb.IPerformanceMetricgetMaximumValue(&maxRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+	pm.MaximumValue = maxResponse.Returnval
+
+	return pm, nil
+}
+
+// metricNames returns names, or the "*" wildcard if names is empty.
+func metricNames(names []string) []string {
+	if len(names) == 0 {
+		return []string{"*"}
+	}
+	return names
+}
+
+func metricObjectIDs(objects []MetricObject) []string {
+	ids := make([]string, len(objects))
+	for i, o := range objects {
+		ids[i] = o.metricObjectID()
+	}
+	return ids
+}
@@ -1,6 +1,11 @@
 package vboxapi
 
-import "github.com/blacktop/go-vboxapi/vboxweb"
+import (
+	"context"
+	"fmt"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
 
 type Progress struct {
 	virtualbox *VirtualBox
@@ -36,3 +41,103 @@ func (p *Progress) GetPercent() (uint32, error) {
 func (p *Progress) Release() error {
 	return p.virtualbox.Release(p.managedObjectId)
 }
+
+// progressPollTimeout is how long, in milliseconds, Wait blocks on the
+// progress before checking its context again.
+const progressPollTimeout = 500
+
+// Wait waits for the operation to complete, cancelling it if ctx is done.
+// If fn is not nil it is called with the completion percentage after each
+// poll.
+// It returns an error if the operation failed or was cancelled.
+func (p *Progress) Wait(ctx context.Context, fn func(percent uint32)) error {
+	for {
+		if err := p.WaitForCompletion(progressPollTimeout); err != nil {
+			return err
+		}
+
+		if fn != nil {
+			percent, err := p.GetPercent()
+			if err != nil {
+				return err
+			}
+			fn(percent)
+		}
+
+		completed, err := p.GetCompleted()
+		if err != nil {
+			return err
+		}
+		if completed {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			if err := p.Cancel(); err != nil {
+				return fmt.Errorf("%w (cancel failed: %v)", ctx.Err(), err)
+			}
+			return ctx.Err()
+		default:
+		}
+	}
+
+	rc, err := p.GetResultCode()
+	if err != nil {
+		return err
+	}
+	if rc != 0 {
+		text, err := p.GetErrorText()
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("operation failed (rc=%#x): %s", uint32(rc), text)
+	}
+
+	return nil
+}
+
+func (p *Progress) GetCompleted() (bool, error) {
+	request := vboxweb.IProgressgetCompleted{This: p.managedObjectId}
+
+	response, err := p.virtualbox.IProgressgetCompleted(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (p *Progress) GetResultCode() (int32, error) {
+	request := vboxweb.IProgressgetResultCode{This: p.managedObjectId}
+
+	response, err := p.virtualbox.IProgressgetResultCode(&request)
+	if err != nil {
+		return 0, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GetErrorText returns the error message of a failed operation.
+func (p *Progress) GetErrorText() (string, error) {
+	request := vboxweb.IProgressgetErrorInfo{This: p.managedObjectId}
+
+	response, err := p.virtualbox.IProgressgetErrorInfo(&request)
+	if err != nil {
+		return "", err // TODO: Wrap the error
+	}
+
+	return errorInfoText(p.virtualbox, response.Returnval)
+}
+
+func (p *Progress) Cancel() error {
+	request := vboxweb.IProgresscancel{This: p.managedObjectId}
+
+	_, err := p.virtualbox.IProgresscancel(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
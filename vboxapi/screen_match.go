@@ -0,0 +1,169 @@
+package vboxapi
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// ScreenMatcher decides whether a captured screen shows what is expected.
+type ScreenMatcher interface {
+	Match(screen image.Image) bool
+}
+
+// ScreenMatcherFunc adapts an ordinary function to a ScreenMatcher.
+type ScreenMatcherFunc func(screen image.Image) bool
+
+func (f ScreenMatcherFunc) Match(screen image.Image) bool {
+	return f(screen)
+}
+
+// PixelMatcher matches when the screen region at At, sized like Reference,
+// differs from Reference by at most Tolerance. The difference is the mean
+// absolute per-channel difference scaled to [0, 1].
+type PixelMatcher struct {
+	Reference image.Image
+	At        image.Point
+	Tolerance float64
+}
+
+func (pm *PixelMatcher) Match(screen image.Image) bool {
+	rb := pm.Reference.Bounds()
+	region, ok := screenRegion(screen, pm.At, rb.Size())
+	if !ok {
+		return false
+	}
+
+	var sum uint64
+	for y := 0; y < rb.Dy(); y++ {
+		for x := 0; x < rb.Dx(); x++ {
+			r1, g1, b1, _ := pm.Reference.At(rb.Min.X+x, rb.Min.Y+y).RGBA()
+			r2, g2, b2, _ := screen.At(region.Min.X+x, region.Min.Y+y).RGBA()
+			sum += absDiff(r1, r2) + absDiff(g1, g2) + absDiff(b1, b2)
+		}
+	}
+	n := uint64(rb.Dx()*rb.Dy()) * 3 * 0xffff
+	if n == 0 {
+		return true
+	}
+	return float64(sum)/float64(n) <= pm.Tolerance
+}
+
+// HashMatcher matches when the difference hash of the screen region at At,
+// sized like Reference, is within MaxDistance bits of the hash of Reference.
+// It tolerates scaling artefacts and small rendering changes better than
+// PixelMatcher.
+type HashMatcher struct {
+	Reference   image.Image
+	At          image.Point
+	MaxDistance int
+
+	once sync.Once
+	hash uint64
+}
+
+func (hm *HashMatcher) Match(screen image.Image) bool {
+	rb := hm.Reference.Bounds()
+	region, ok := screenRegion(screen, hm.At, rb.Size())
+	if !ok {
+		return false
+	}
+	hm.once.Do(func() {
+		hm.hash = dHash(hm.Reference, rb)
+	})
+	return bits.OnesCount64(hm.hash^dHash(screen, region)) <= hm.MaxDistance
+}
+
+// defaultScreenPollInterval is used by WaitForScreen when no positive
+// interval is given.
+const defaultScreenPollInterval = time.Second
+
+// WaitForScreen captures the primary screen every interval until matcher
+// matches or ctx is done. Failed captures are retried; if ctx ends first,
+// the last capture error is reported along with ctx.Err().
+func (d *Display) WaitForScreen(ctx context.Context, matcher ScreenMatcher, interval time.Duration) (image.Image, error) {
+	if interval <= 0 {
+		interval = defaultScreenPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		screen, err := d.Screenshot(ctx, 0)
+		if err == nil && matcher.Match(screen) {
+			return screen, nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w (last screenshot error: %v)", ctx.Err(), lastErr)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// screenRegion returns the rectangle of the given size at p in screen, and
+// whether it lies entirely within the screen.
+func screenRegion(screen image.Image, p image.Point, size image.Point) (image.Rectangle, bool) {
+	sb := screen.Bounds()
+	r := image.Rectangle{Min: sb.Min.Add(p), Max: sb.Min.Add(p).Add(size)}
+	return r, r.In(sb)
+}
+
+// dHash computes a 64-bit difference hash of the region r of img by
+// comparing neighbouring cells of a 9x8 grid of average luminance.
+func dHash(img image.Image, r image.Rectangle) uint64 {
+	var grid [8][9]uint64
+	for gy := 0; gy < 8; gy++ {
+		for gx := 0; gx < 9; gx++ {
+			cell := image.Rect(
+				r.Min.X+gx*r.Dx()/9, r.Min.Y+gy*r.Dy()/8,
+				r.Min.X+(gx+1)*r.Dx()/9, r.Min.Y+(gy+1)*r.Dy()/8,
+			)
+			grid[gy][gx] = averageLuma(img, cell)
+		}
+	}
+
+	var hash uint64
+	for gy := 0; gy < 8; gy++ {
+		for gx := 0; gx < 8; gx++ {
+			hash <<= 1
+			if grid[gy][gx] > grid[gy][gx+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+func averageLuma(img image.Image, r image.Rectangle) uint64 {
+	if r.Empty() {
+		r.Max = r.Min.Add(image.Pt(1, 1))
+	}
+	var sum, n uint64
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			sum += (299*uint64(cr) + 587*uint64(cg) + 114*uint64(cb)) / 1000
+			n++
+		}
+	}
+	return sum / n
+}
+
+func absDiff(a, b uint32) uint64 {
+	if a > b {
+		return uint64(a - b)
+	}
+	return uint64(b - a)
+}
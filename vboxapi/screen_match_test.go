@@ -0,0 +1,92 @@
+package vboxapi
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient returns a w x h image whose brightness rises from left to right,
+// or falls if reverse is set.
+func gradient(w, h int, reverse bool) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := uint8(x * 255 / (w - 1))
+			if reverse {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func uniform(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		want uint64
+	}{
+		{"uniform", uniform(36, 16, color.White), 0},
+		{"rising", gradient(36, 16, false), 0},
+		{"falling", gradient(36, 16, true), ^uint64(0)},
+		{"tiny", uniform(3, 2, color.Black), 0},
+	}
+
+	for _, tt := range tests {
+		if got := dHash(tt.img, tt.img.Bounds()); got != tt.want {
+			t.Errorf("dHash(%s) = %#016x, want %#016x", tt.name, got, tt.want)
+		}
+	}
+
+	// The hash is independent of the region's position and scale.
+	screen := image.NewGray(image.Rect(0, 0, 100, 100))
+	for y := 10; y < 26; y++ {
+		for x := 20; x < 92; x++ {
+			screen.SetGray(x, y, color.Gray{Y: uint8(255 - (x-20)*255/71)})
+		}
+	}
+	if got := dHash(screen, image.Rect(20, 10, 92, 26)); got != ^uint64(0) {
+		t.Errorf("dHash(region) = %#016x, want %#016x", got, ^uint64(0))
+	}
+}
+
+func TestPixelMatcher(t *testing.T) {
+	screen := uniform(10, 10, color.Black)
+	for y := 2; y < 4; y++ {
+		for x := 2; x < 4; x++ {
+			screen.Set(x, y, color.White)
+		}
+	}
+
+	tests := []struct {
+		name string
+		pm   PixelMatcher
+		want bool
+	}{
+		{"exact", PixelMatcher{Reference: uniform(2, 2, color.White), At: image.Pt(2, 2)}, true},
+		{"offset", PixelMatcher{Reference: uniform(2, 2, color.White), At: image.Pt(3, 3)}, false},
+		{"tolerated", PixelMatcher{Reference: uniform(2, 2, color.White), At: image.Pt(3, 3), Tolerance: 0.75}, true},
+		{"grey", PixelMatcher{Reference: uniform(2, 2, color.Gray{Y: 0xf0}), At: image.Pt(2, 2), Tolerance: 0.1}, true},
+		{"grey strict", PixelMatcher{Reference: uniform(2, 2, color.Gray{Y: 0xf0}), At: image.Pt(2, 2)}, false},
+		{"outside", PixelMatcher{Reference: uniform(2, 2, color.White), At: image.Pt(9, 9), Tolerance: 1}, false},
+		{"empty", PixelMatcher{Reference: uniform(0, 0, color.White)}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.pm.Match(screen); got != tt.want {
+			t.Errorf("PixelMatcher(%s).Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -44,6 +44,18 @@ func (s *Session) GetMachine() (*Machine, error) {
 	return &Machine{managedObjectId: response.Returnval, virtualbox: s.virtualbox}, nil
 }
 
+// GetConsole returns the console of the VM locked by the session. The
+// session must hold a shared lock on a running VM.
+func (s *Session) GetConsole() (*Console, error) {
+	request := vboxweb.ISessiongetConsole{This: s.managedObjectId}
+	response, err := s.virtualbox.ISessiongetConsole(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Console{virtualbox: s.virtualbox, managedObjectID: response.Returnval}, nil
+}
+
 func (s *Session) Release() error {
 	return s.virtualbox.Release(s.managedObjectId)
 }
@@ -38,23 +38,90 @@ func (sc *StorageController) GetPortCount() (uint32, error) {
 	return response.Returnval, nil
 }
 
-func (sc *StorageController) GetStorageBus() (vboxweb.StorageBus, error) {
-	mapStorageBus := make(map[string]vboxweb.StorageBus)
-	mapStorageBus["SATA Controller"] = vboxweb.StorageBusSATA
-	mapStorageBus["IDE Controller"] = vboxweb.StorageBusIDE
-	mapStorageBus["SCSI"] = vboxweb.StorageBusSCSI
-	mapStorageBus["SAS"] = vboxweb.StorageBusSAS
+// Storage buses and controller types introduced after the generated
+// bindings. The server accepts them as plain enum strings.
+const (
+	StorageBusPCIe vboxweb.StorageBus = "PCIe"
+
+	StorageControllerTypeNVMe vboxweb.StorageControllerType = "NVMe"
+)
+
+// GetBus returns the bus the controller is connected to.
+func (sc *StorageController) GetBus() (*vboxweb.StorageBus, error) {
+	request := vboxweb.IStorageControllergetBus{This: sc.managedObjectId}
 
-	scName, err := sc.GetName()
+	response, err := sc.virtualbox.IStorageControllergetBus(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+// GetStorageBus returns the bus the controller is connected to.
+//
+// Deprecated: use GetBus.
+func (sc *StorageController) GetStorageBus() (vboxweb.StorageBus, error) {
+	bus, err := sc.GetBus()
 	if err != nil {
 		return vboxweb.StorageBusNull, err
 	}
+	return *bus, nil
+}
+
+func (sc *StorageController) GetControllerType() (*vboxweb.StorageControllerType, error) {
+	request := vboxweb.IStorageControllergetControllerType{This: sc.managedObjectId}
+
+	response, err := sc.virtualbox.IStorageControllergetControllerType(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (sc *StorageController) SetControllerType(controllerType vboxweb.StorageControllerType) error {
+	request := vboxweb.IStorageControllersetControllerType{This: sc.managedObjectId, ControllerType: &controllerType}
 
-	if bus, ok := mapStorageBus[scName]; ok {
-		return bus, nil
+	_, err := sc.virtualbox.IStorageControllersetControllerType(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
 	}
 
-	return vboxweb.StorageBusNull, errors.New("bad controller controller specified")
+	return nil
+}
+
+func (sc *StorageController) GetUseHostIOCache() (bool, error) {
+	request := vboxweb.IStorageControllergetUseHostIOCache{This: sc.managedObjectId}
+
+	response, err := sc.virtualbox.IStorageControllergetUseHostIOCache(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
+}
+
+func (sc *StorageController) SetUseHostIOCache(useHostIOCache bool) error {
+	request := vboxweb.IStorageControllersetUseHostIOCache{This: sc.managedObjectId, UseHostIOCache: useHostIOCache}
+
+	_, err := sc.virtualbox.IStorageControllersetUseHostIOCache(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+func (sc *StorageController) GetBootable() (bool, error) {
+	request := vboxweb.IStorageControllergetBootable{This: sc.managedObjectId}
+
+	response, err := sc.virtualbox.IStorageControllergetBootable(&request)
+	if err != nil {
+		return false, err // TODO: Wrap the error
+	}
+
+	return response.Returnval, nil
 }
 
 func (sc *StorageController) GetMaxPortCount() (uint32, error) {
@@ -79,7 +146,21 @@ func (sc *StorageController) SetPortCount(count uint32) error {
 	return nil
 }
 
+// GetNextAvailablePort returns the first port of the controller with no
+// device attached.
 func (sc *StorageController) GetNextAvailablePort(m *Machine) (int32, error) {
+	return sc.nextAvailablePort(m, func(am *vboxweb.IMediumAttachment) bool { return true })
+}
+
+// GetNextAvailablePortForDevice returns the first port of the controller
+// whose slot device is free.
+func (sc *StorageController) GetNextAvailablePortForDevice(m *Machine, device int32) (int32, error) {
+	return sc.nextAvailablePort(m, func(am *vboxweb.IMediumAttachment) bool { return am.Device == device })
+}
+
+// nextAvailablePort returns the first port of the controller none of whose
+// attachments satisfy occupies.
+func (sc *StorageController) nextAvailablePort(m *Machine, occupies func(am *vboxweb.IMediumAttachment) bool) (int32, error) {
 	c, err := sc.GetMaxPortCount()
 	if err != nil {
 		return 0, err
@@ -87,18 +168,19 @@ func (sc *StorageController) GetNextAvailablePort(m *Machine) (int32, error) {
 
 	ams, err := m.GetMediumAttachmentsOfController(sc.Name)
 	if err != nil {
-		return 0, nil
+		return 0, err
 	}
 
-	portMap := make(map[int32]bool)
+	used := make(map[int32]bool)
 	for _, am := range ams {
-		portMap[am.Port] = true
+		if occupies(am) {
+			used[am.Port] = true
+		}
 	}
 
-	intArr := make([]int32, c)
-	for i := range intArr {
-		if _, isUsed := portMap[int32(i)]; !isUsed {
-			return int32(i), nil
+	for port := int32(0); port < int32(c); port++ {
+		if !used[port] {
+			return port, nil
 		}
 	}
 	return 0, errors.New("no available ports")
@@ -0,0 +1,37 @@
+package vboxapi
+
+import "github.com/blacktop/go-vboxapi/vboxweb"
+
+// Token is a VirtualBox token object. It holds a lock on a medium until it
+// is closed.
+type Token struct {
+	virtualbox      *VirtualBox
+	managedObjectId string
+}
+
+// Abandon releases the lock held by the token.
+func (t *Token) Abandon() error {
+	request := vboxweb.ITokenabandon{This: t.managedObjectId}
+
+	_, err := t.virtualbox.ITokenabandon(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// Close abandons the token and releases its managed object reference. The
+// reference is released even if abandoning fails; the first error is
+// returned.
+func (t *Token) Close() error {
+	err := t.Abandon()
+	if rerr := t.Release(); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+func (t *Token) Release() error {
+	return t.virtualbox.Release(t.managedObjectId)
+}
@@ -0,0 +1,168 @@
+package vboxapi
+
+import (
+	"context"
+
+	"github.com/blacktop/go-vboxapi/vboxweb"
+)
+
+// ExtraDataCanChangeEvent asks whether extra data of a machine, or of
+// VirtualBox itself if MachineID is empty, may be changed.
+type ExtraDataCanChangeEvent struct {
+	BaseEvent
+	MachineID string
+	Key       string
+	Value     string
+}
+
+// CanShowWindowEvent asks whether the VM's console window may be shown.
+type CanShowWindowEvent struct {
+	BaseEvent
+}
+
+// VetoDecision is the answer of a VetoFunc. The zero value neither vetoes
+// nor approves.
+type VetoDecision struct {
+	Vetoed   bool
+	Approved bool
+	Reason   string
+}
+
+// Veto returns a decision vetoing the event for the given reason.
+func Veto(reason string) VetoDecision {
+	return VetoDecision{Vetoed: true, Reason: reason}
+}
+
+// Approve returns a decision approving the event for the given reason.
+func Approve(reason string) VetoDecision {
+	return VetoDecision{Approved: true, Reason: reason}
+}
+
+// VetoFunc inspects a vetoable event and decides on it. Events that could
+// not be decoded are passed as *EventError.
+type VetoFunc func(ev Event) VetoDecision
+
+// HandleVetoable registers fn on the global event source for extra-data
+// changes. See EventSource.HandleVetoable.
+func (vb *VirtualBox) HandleVetoable(ctx context.Context, fn VetoFunc) (<-chan error, error) {
+	es, err := vb.GetEventSource()
+	if err != nil {
+		return nil, err
+	}
+	return es.handleVetoable(ctx, true, fn, []vboxweb.VBoxEventType{vboxweb.VBoxEventTypeOnExtraDataCanChange})
+}
+
+// HandleVetoable registers a passive listener for the given vetoable event
+// types, or for ExtraDataCanChange and CanShowWindow if none are given, and
+// calls fn for every event before it is marked processed, so VirtualBox
+// sees the decision. fn is called from a single goroutine and should
+// return quickly as the event's originator waits for it. When ctx is done
+// the listener is unregistered and the returned channel closed. Failures
+// to record a decision are sent on the channel, which must be drained; a
+// failure to fetch events is sent on it before it is closed.
+func (es *EventSource) HandleVetoable(ctx context.Context, fn VetoFunc, types ...vboxweb.VBoxEventType) (<-chan error, error) {
+	if len(types) == 0 {
+		types = []vboxweb.VBoxEventType{vboxweb.VBoxEventTypeOnExtraDataCanChange, vboxweb.VBoxEventTypeOnCanShowWindow}
+	}
+	return es.handleVetoable(ctx, false, fn, types)
+}
+
+// handleVetoable implements HandleVetoable. If owned is set the event
+// source is released together with the listener.
+func (es *EventSource) handleVetoable(ctx context.Context, owned bool, fn VetoFunc, types []vboxweb.VBoxEventType) (<-chan error, error) {
+	l, err := es.listen(types)
+	if err != nil {
+		if owned {
+			es.Release()
+		}
+		return nil, err
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer func() {
+			l.close()
+			if owned {
+				es.Release()
+			}
+		}()
+
+		send := func(err error) bool {
+			select {
+			case errc <- err:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for ctx.Err() == nil {
+			moid, err := l.next()
+			if err != nil {
+				send(err)
+				return
+			}
+			if moid == "" {
+				continue
+			}
+
+			var applyErr error
+			l.handle(moid, func(moid string, ev Event) {
+				applyErr = es.virtualbox.applyVetoDecision(moid, fn(ev))
+			})
+			if applyErr != nil && !send(applyErr) {
+				return
+			}
+		}
+	}()
+	return errc, nil
+}
+
+// applyVetoDecision records d on the veto event object moid.
+func (vb *VirtualBox) applyVetoDecision(moid string, d VetoDecision) error {
+	switch {
+	case d.Vetoed:
+		request := vboxweb.IVetoEventaddVeto{This: moid, Reason: d.Reason}
+
+		_, err := vb.IVetoEventaddVeto(&request)
+		return err // TODO: Wrap the error
+	case d.Approved:
+		request := vboxweb.IVetoEventaddApproval{This: moid, Reason: d.Reason}
+
+		_, err := vb.IVetoEventaddApproval(&request)
+		return err // TODO: Wrap the error
+	}
+	return nil
+}
+
+func decodeExtraDataCanChange(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	machineRequest := vboxweb.IExtraDataCanChangeEventgetMachineId{This: moid}
+	machineResponse, err := vb.IExtraDataCanChangeEventgetMachineId(&machineRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	keyRequest := vboxweb.IExtraDataCanChangeEventgetKey{This: moid}
+	keyResponse, err := vb.IExtraDataCanChangeEventgetKey(&keyRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	valueRequest := vboxweb.IExtraDataCanChangeEventgetValue{This: moid}
+	valueResponse, err := vb.IExtraDataCanChangeEventgetValue(&valueRequest)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &ExtraDataCanChangeEvent{
+		BaseEvent: base,
+		MachineID: machineResponse.Returnval,
+		Key:       keyResponse.Returnval,
+		Value:     valueResponse.Returnval,
+	}, nil
+}
+
+func decodeCanShowWindow(vb *VirtualBox, moid string, base BaseEvent) (Event, error) {
+	return &CanShowWindowEvent{BaseEvent: base}, nil
+}
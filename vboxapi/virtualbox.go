@@ -13,6 +13,9 @@ type VirtualBox struct {
 	controllerName  string
 }
 
+// New returns a VirtualBox client for the vboxwebsrv at url. controllerName
+// is the default storage controller used by Machine.AttachDevice when
+// AttachOptions does not name one.
 func New(username, password, url string, tls bool, controllerName string) *VirtualBox {
 	basicAuth := &vboxweb.BasicAuth{
 		Login:    username,
@@ -71,6 +74,17 @@ func (vb *VirtualBox) GetSystemProperties() (*SystemProperties, error) {
 	return &SystemProperties{vb, response.Returnval}, nil
 }
 
+func (vb *VirtualBox) GetHost() (*Host, error) {
+	request := vboxweb.IVirtualBoxgetHost{This: vb.managedObjectId}
+
+	response, err := vb.IVirtualBoxgetHost(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &Host{vb, response.Returnval}, nil
+}
+
 func (vb *VirtualBox) Logon() error {
 	request := vboxweb.IWebsessionManagerlogon{
 		Username: vb.basicAuth.Login,
@@ -213,3 +227,103 @@ func (vb *VirtualBox) GetMOID() string {
 func (vb *VirtualBox) NewMedium(moid string) *Medium {
 	return &Medium{virtualbox: vb, managedObjectId: moid}
 }
+
+func (vb *VirtualBox) CreateNATNetwork(networkName string) (*NATNetwork, error) {
+	request := vboxweb.IVirtualBoxcreateNATNetwork{This: vb.managedObjectId, NetworkName: networkName}
+
+	response, err := vb.IVirtualBoxcreateNATNetwork(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &NATNetwork{virtualbox: vb, managedObjectId: response.Returnval}, nil
+}
+
+func (vb *VirtualBox) FindNATNetworkByName(networkName string) (*NATNetwork, error) {
+	request := vboxweb.IVirtualBoxfindNATNetworkByName{This: vb.managedObjectId, NetworkName: networkName}
+
+	response, err := vb.IVirtualBoxfindNATNetworkByName(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &NATNetwork{virtualbox: vb, managedObjectId: response.Returnval}, nil
+}
+
+func (vb *VirtualBox) GetNATNetworks() ([]*NATNetwork, error) {
+	request := vboxweb.IVirtualBoxgetNATNetworks{This: vb.managedObjectId}
+
+	response, err := vb.IVirtualBoxgetNATNetworks(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	networks := make([]*NATNetwork, len(response.Returnval))
+	for n, oid := range response.Returnval {
+		networks[n] = &NATNetwork{virtualbox: vb, managedObjectId: oid}
+	}
+
+	return networks, nil
+}
+
+func (vb *VirtualBox) RemoveNATNetwork(network *NATNetwork) error {
+	request := vboxweb.IVirtualBoxremoveNATNetwork{This: vb.managedObjectId, Network: network.managedObjectId}
+
+	_, err := vb.IVirtualBoxremoveNATNetwork(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
+
+// CreateDHCPServer creates a DHCP server for the network named name, such as
+// "HostInterfaceNetworking-vboxnet0" for a host-only interface.
+func (vb *VirtualBox) CreateDHCPServer(name string) (*DHCPServer, error) {
+	request := vboxweb.IVirtualBoxcreateDHCPServer{This: vb.managedObjectId, Name: name}
+
+	response, err := vb.IVirtualBoxcreateDHCPServer(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &DHCPServer{virtualbox: vb, managedObjectId: response.Returnval}, nil
+}
+
+func (vb *VirtualBox) FindDHCPServerByNetworkName(name string) (*DHCPServer, error) {
+	request := vboxweb.IVirtualBoxfindDHCPServerByNetworkName{This: vb.managedObjectId, Name: name}
+
+	response, err := vb.IVirtualBoxfindDHCPServerByNetworkName(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	return &DHCPServer{virtualbox: vb, managedObjectId: response.Returnval}, nil
+}
+
+func (vb *VirtualBox) GetDHCPServers() ([]*DHCPServer, error) {
+	request := vboxweb.IVirtualBoxgetDHCPServers{This: vb.managedObjectId}
+
+	response, err := vb.IVirtualBoxgetDHCPServers(&request)
+	if err != nil {
+		return nil, err // TODO: Wrap the error
+	}
+
+	servers := make([]*DHCPServer, len(response.Returnval))
+	for n, oid := range response.Returnval {
+		servers[n] = &DHCPServer{virtualbox: vb, managedObjectId: oid}
+	}
+
+	return servers, nil
+}
+
+func (vb *VirtualBox) RemoveDHCPServer(server *DHCPServer) error {
+	request := vboxweb.IVirtualBoxremoveDHCPServer{This: vb.managedObjectId, Server: server.managedObjectId}
+
+	_, err := vb.IVirtualBoxremoveDHCPServer(&request)
+	if err != nil {
+		return err // TODO: Wrap the error
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"reflect"
 	"time"
 )
 
@@ -3324,9 +3325,9 @@ type IVirtualSystemDescriptionsetFinalValues struct {
 	XMLName xml.Name `xml:"http://www.virtualbox.org/ IVirtualSystemDescription_setFinalValues"`
 
 	This              string   `xml:"_this,omitempty"`
-	Enabled           []bool   `xml:"enabled,omitempty"`
-	VBoxValues        []string `xml:"VBoxValues,omitempty"`
-	ExtraConfigValues []string `xml:"extraConfigValues,omitempty"`
+	Enabled           []bool   `xml:"enabled"`
+	VBoxValues        []string `xml:"VBoxValues"`
+	ExtraConfigValues []string `xml:"extraConfigValues"`
 }
 
 type IVirtualSystemDescriptionsetFinalValuesResponse struct {
@@ -17223,6 +17224,12 @@ func NewVboxPortType(url string, tls bool, auth *BasicAuth) *VboxPortType {
 	}
 }
 
+// SetCallObserver installs fn to be called after every SOAP call. It must
+// be set before the service is used concurrently.
+func (service *VboxPortType) SetCallObserver(fn CallObserver) {
+	service.client.observer = fn
+}
+
 // Error can be either of the following types:
 //
 //   - InvalidObjectFault
@@ -36091,10 +36098,17 @@ type SOAPBody struct {
 type SOAPFault struct {
 	XMLName xml.Name `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault"`
 
-	Code   string `xml:"faultcode,omitempty"`
-	String string `xml:"faultstring,omitempty"`
-	Actor  string `xml:"faultactor,omitempty"`
-	Detail string `xml:"detail,omitempty"`
+	Code   string           `xml:"faultcode,omitempty"`
+	String string           `xml:"faultstring,omitempty"`
+	Actor  string           `xml:"faultactor,omitempty"`
+	Detail *SOAPFaultDetail `xml:"detail,omitempty"`
+}
+
+// SOAPFaultDetail holds the VirtualBox specific fault carried in the detail
+// of a SOAP fault, if any.
+type SOAPFaultDetail struct {
+	RuntimeFault       *RuntimeFault       `xml:"http://www.virtualbox.org/ RuntimeFault,omitempty"`
+	InvalidObjectFault *InvalidObjectFault `xml:"http://www.virtualbox.org/ InvalidObjectFault,omitempty"`
 }
 
 type BasicAuth struct {
@@ -36102,10 +36116,16 @@ type BasicAuth struct {
 	Password string
 }
 
+// CallObserver is called after every SOAP call with the name of the request
+// type, e.g. "IVirtualBoxgetHost", the time the call took and its error.
+// A fault returned by vboxwebsrv is passed as a *SOAPFault.
+type CallObserver func(method string, duration time.Duration, err error)
+
 type SOAPClient struct {
-	url  string
-	tls  bool
-	auth *BasicAuth
+	url      string
+	tls      bool
+	auth     *BasicAuth
+	observer CallObserver
 }
 
 func (b *SOAPBody) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
@@ -36171,6 +36191,17 @@ func NewSOAPClient(url string, tls bool, auth *BasicAuth) *SOAPClient {
 }
 
 func (s *SOAPClient) Call(soapAction string, request, response interface{}) error {
+	if s.observer == nil {
+		return s.call(soapAction, request, response)
+	}
+
+	start := time.Now()
+	err := s.call(soapAction, request, response)
+	s.observer(reflect.Indirect(reflect.ValueOf(request)).Type().Name(), time.Since(start), err)
+	return err
+}
+
+func (s *SOAPClient) call(soapAction string, request, response interface{}) error {
 	envelope := SOAPEnvelope{
 	//Header:        SoapHeader{},
 	}